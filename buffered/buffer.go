@@ -10,18 +10,23 @@ import (
 	"sync"
 
 	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/internal/errs"
+	"github.com/corvus-ch/logr/internal/kv"
+	"github.com/corvus-ch/logr/verbosity"
 )
 
 const (
 	levelError = "ERROR "
 	levelInfo  = "INFO "
+	levelWarn  = "WARN "
 	levelV     = "V[%d] "
 )
 
 // New creates a new logr.Logger instance.
 //
-// The verbosity defines the upper limit. When creating a new sub logger using logr.Logger.V(), if the level passed to
-// V is greater than verbosity, the sub logger will be silenced.
+// The verbosity v defines the upper limit. When creating a new sub logger using logr.Logger.V(), if the level passed
+// to V is greater than the verbosity, the sub logger will be silenced. The verbosity is shared by all loggers derived
+// from the returned logger and can be changed at runtime using Verbosity.
 //
 // Each written line will be prefixed depending of the loggers current level. When using logr.Logger.Error or
 // logr.Logger.Errorf, the prefix will be "ERROR". When using logr.Logger.Info or logr.Logger.Infof and if the level is
@@ -29,31 +34,46 @@ const (
 // current logger level.
 //
 // When creating a new sub logger using logr.Logger.NewWithPrefix(), the prefix will be written after the logging level
-// but before the message. No whitespace is added between the prefix and the message.
+// but before the message. No whitespace is added between the prefix and the message. Calling NewWithPrefix on a logger
+// which already has a prefix, joins both prefixes using the separator. The separator defaults to "." and can be changed
+// using SetSeparator.
 //
+// Key/value pairs added using WithValues or WithField are appended to the message as space separated list of key=value.
 //
-func New(verbosity int) *logger {
+// If one of the arguments passed to Error or Errorf is an error wrapping other errors, the messages of the wrapped
+// errors are written on separate, indented lines below the message. If enabled using SetStackTrace, the stack trace of
+// the call to Error or Errorf is written below as well.
+//
+// The logger and all sub loggers derived from it share the same buffer and the same sync.Mutex. It is therefore safe to
+// use them concurrently.
+func New(v int) *logger {
 	return &logger{
 		level:     0,
-		verbosity: verbosity,
+		verbosity: verbosity.New(v),
 		prefix:    "",
+		separator: ".",
 		buf:       &bytes.Buffer{},
+		mu:        &sync.Mutex{},
 	}
 }
 
 type logger struct {
 	logr.Logger
 	level     int
-	verbosity int
+	verbosity *verbosity.Level
 	prefix    string
+	separator string
+	values    []kv.Pair
+	stack     bool
+	skip      int
 	buf       *bytes.Buffer
-	mu        sync.Mutex
+	mu        *sync.Mutex
 }
 
 // Info implements logr.Logger.Info by writing the line to the internal buffer.
 func (l logger) Info(args ...interface{}) {
 	if l.Enabled() {
-		l.writeLine(l.levelString(), fmt.Sprint(args...))
+		l.writeLine(l.levelString(), fmt.Sprint(args...), "")
 	}
 }
 
@@ -63,48 +83,83 @@ func (l logger) Info(args ...interface{}) {
 // For levels above zero, the prefix will be V[<level>] where <level> will be the current logger level.
 func (l logger) Infof(format string, args ...interface{}) {
 	if l.Enabled() {
-		l.writeLine(l.levelString(), fmt.Sprintf(format, args...))
+		l.writeLine(l.levelString(), fmt.Sprintf(format, args...), "")
 	}
 }
 
 // Enabled implements logr.Logger.Enabled by checking if the current verbosity level is less or equal than the loggers
-// maximum verbosity.
+// maximum verbosity for the loggers prefix. The maximum verbosity can be changed at any time using Verbosity, including
+// per prefix overrides.
 func (l logger) Enabled() bool {
-	return l.level <= l.verbosity
+	return l.verbosity.EnabledFor(l.prefix, l.level)
 }
 
 // Error implements logr.Logger.Error by prefixing the line with "ERROR" and write it to the internal buffer.
 func (l logger) Error(args ...interface{}) {
-	l.writeLine(levelError, fmt.Sprint(args...))
+	l.error(fmt.Sprint(args...), errs.Find(args))
 }
 
 // Error implements logr.Logger.Errorf by prefixing the line with "ERROR" and write it to the internal buffer.
 func (l logger) Errorf(format string, args ...interface{}) {
-	l.writeLine(levelError, fmt.Sprintf(format, args...))
+	l.error(fmt.Sprintf(format, args...), errs.Find(args))
+}
+
+// Warn implements warn.Logger.Warn by prefixing the line with "WARN" and write it to the internal buffer.
+func (l logger) Warn(args ...interface{}) {
+	l.writeLine(levelWarn, fmt.Sprint(args...), "")
+}
+
+// Warnf implements warn.Logger.Warnf by prefixing the line with "WARN" and write it to the internal buffer.
+func (l logger) Warnf(format string, args ...interface{}) {
+	l.writeLine(levelWarn, fmt.Sprintf(format, args...), "")
 }
 
 // V implements logr.Logger.V.
 func (l logger) V(level int) logr.InfoLogger {
-	return logger{
-		level:     level,
-		verbosity: l.verbosity,
-		prefix:    l.prefix,
-		buf:       l.buf,
-		mu:        l.mu,
-	}
+	l.level = level
+	return l
 }
 
-// NewWithPrefix implements logr.Logger.NewWithPrefix.
-//
-//
+// NewWithPrefix implements logr.Logger.NewWithPrefix by joining the prefix with the current one.
 func (l logger) NewWithPrefix(prefix string) logr.Logger {
-	return logger{
-		level:     l.level,
-		verbosity: l.verbosity,
-		prefix:    prefix,
-		buf:       l.buf,
-		mu:        l.mu,
+	if len(l.prefix) > 0 {
+		prefix = l.prefix + l.separator + prefix
 	}
+	l.prefix = prefix
+	return l
+}
+
+// WithValues returns a logger which appends the given key/value pairs to each line.
+func (l logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	l.values = kv.Append(l.values, kv.Pairs(keysAndValues...)...)
+	return l
+}
+
+// WithField implements logr.Logger.WithField by calling WithValues with a single key/value pair.
+func (l logger) WithField(name string, value interface{}) logr.Logger {
+	return l.WithValues(name, value)
+}
+
+// WithCallerSkip returns a logger which skips additional stack frames when capturing the stack trace. Wrappers around
+// the logger use it to have the stack trace start at the caller of the wrapper.
+func (l logger) WithCallerSkip(skip int) logr.Logger {
+	l.skip += skip
+	return l
+}
+
+// Verbosity returns the verbosity level shared by this logger and all loggers derived from it.
+func (l logger) Verbosity() *verbosity.Level {
+	return l.verbosity
+}
+
+// SetSeparator sets the separator used to join prefixes of nested calls to NewWithPrefix.
+func (l *logger) SetSeparator(sep string) {
+	l.separator = sep
+}
+
+// SetStackTrace enables or disables capturing the stack trace on calls to Error and Errorf.
+func (l *logger) SetStackTrace(enabled bool) {
+	l.stack = enabled
 }
 
 // Buf returns the internal buffer.
@@ -115,19 +170,32 @@ func (l logger) Buf() *bytes.Buffer {
 }
 
 // Mutex returns the sync.Mutex used to preserve the order of writes to the buffer.
-func (l *logger) Mutex() *sync.Mutex {
-	return &l.mu
+//
+// The mutex is shared with all sub loggers created using V, NewWithPrefix or WithValues.
+func (l logger) Mutex() *sync.Mutex {
+	return l.mu
+}
+
+func (l logger) error(msg string, err error) {
+	var stack string
+	if l.stack {
+		stack = errs.Stack(2 + l.skip)
+	}
+	l.writeLine(levelError, msg, errs.Details(err, stack))
 }
 
-func (l logger) writeLine(level, line string) {
+func (l logger) writeLine(level, line, details string) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.buf.WriteString(level)
 	l.buf.WriteString(l.prefix)
-	l.buf.WriteString(line)
-	if !strings.HasSuffix(line, "\n") {
-		l.buf.WriteRune('\n')
+	l.buf.WriteString(strings.TrimSuffix(line, "\n"))
+	if len(l.values) > 0 {
+		l.buf.WriteRune(' ')
+		l.buf.WriteString(kv.Format(l.values))
 	}
+	l.buf.WriteString(details)
+	l.buf.WriteRune('\n')
 }
 
 func (l logger) levelString() string {
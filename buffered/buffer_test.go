@@ -1,9 +1,15 @@
 package buffered_test
 
 import (
+	"fmt"
+	"io/fs"
 	"os"
+	"strings"
+	"sync"
+	"testing"
 
 	log "github.com/corvus-ch/logr/buffered"
+	"github.com/stretchr/testify/assert"
 )
 
 func Example() {
@@ -45,3 +51,78 @@ func Example_mutex() {
 	l.Buf().WriteTo(os.Stdout)
 	// Output: Duis mollis, est non commodo luctus, nisi erat porttitor ligula, eget lacinia odio sem nec elit.
 }
+
+func Example_withValues() {
+	l := log.New(0)
+	l.WithValues("user", "jane", "attempt", 3).Info("Login failed")
+	l.WithField("path", "/var/lib/dolor sit").Error("Not found")
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO Login failed user=jane attempt=3
+	// ERROR Not found path="/var/lib/dolor sit"
+}
+
+func Example_nestedPrefix() {
+	l := log.New(0)
+	db := l.NewWithPrefix("db")
+	db.NewWithPrefix("pool").Info(": Connection established")
+	l.SetSeparator("/")
+	l.NewWithPrefix("http").NewWithPrefix("server").Info(": Listening")
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO db.pool: Connection established
+	// INFO http/server: Listening
+}
+
+func Example_error() {
+	l := log.New(0)
+	err := fmt.Errorf("read config: %w", &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist})
+	l.WithValues("attempt", 3).Error(err)
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// ERROR read config: open /etc/app.conf: file does not exist attempt=3
+	//     caused by: open /etc/app.conf: file does not exist
+	//     caused by: file does not exist
+}
+
+func TestStackTrace(t *testing.T) {
+	l := log.New(0)
+	l.SetStackTrace(true)
+	l.Error("Error level log message")
+	lines := strings.Split(l.Buf().String(), "\n")
+	assert.Equal(t, "ERROR Error level log message", lines[0])
+	assert.Equal(t, "    stack:", lines[1])
+	assert.Equal(t, "        github.com/corvus-ch/logr/buffered_test.TestStackTrace", lines[2])
+	assert.Regexp(t, `^        \t.*/buffered/buffer_test.go:\d+$`, lines[3])
+}
+
+func TestConcurrent(t *testing.T) {
+	const n = 500
+	l := log.New(1)
+	writers := []func(args ...interface{}){
+		l.Info,
+		l.Error,
+		l.V(1).Info,
+		l.NewWithPrefix("adipiscing").Info,
+		l.NewWithPrefix("adipiscing").Error,
+		l.NewWithPrefix("adipiscing").V(1).Info,
+		l.WithValues("key", "value").Info,
+	}
+	var wg sync.WaitGroup
+	for _, write := range writers {
+		wg.Add(1)
+		go func(write func(args ...interface{})) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				write(i)
+			}
+		}(write)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(l.Buf().String(), "\n"), "\n")
+	assert.Len(t, lines, n*len(writers))
+	for _, line := range lines {
+		assert.Regexp(t, `^(INFO|ERROR|V\[1\]) (adipiscing)?\d+( key=value)?$`, line)
+	}
+}
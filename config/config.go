@@ -0,0 +1,229 @@
+// Package config builds a logr.Logger by Tim Hockin from a declarative configuration.
+//
+// The Config can be embedded into the configuration of an application and decoded from JSON or YAML, or parsed on its
+// own using ParseJSON and ParseYAML:
+//
+//	c, err := config.ParseYAML([]byte("backend: zap\nverbosity: 1\nformat: json\nmodules: db.*=4"))
+//	if err != nil {
+//		panic(err)
+//	}
+//	l, closeOutput, err := config.New(c)
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer closeOutput()
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	stdlog "log"
+	"os"
+	"strings"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/logrus"
+	"github.com/corvus-ch/logr/std"
+	"github.com/corvus-ch/logr/verbosity"
+	logzap "github.com/corvus-ch/logr/zap"
+	logzerolog "github.com/corvus-ch/logr/zerolog"
+	"github.com/rs/zerolog"
+	sirupsen "github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// The backends supported by New.
+const (
+	BackendStd     = "std"
+	BackendZap     = "zap"
+	BackendZerolog = "zerolog"
+	BackendLogrus  = "logrus"
+)
+
+// The formats supported by New. FormatJSON is not supported by BackendStd.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// The special values of Config.Output. Any other value is used as path of a file the output is appended to.
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// Config describes a logr.Logger. The zero value describes a logger using BackendStd writing text to stderr with
+// verbosity 0.
+type Config struct {
+	// Backend is one of BackendStd, BackendZap, BackendZerolog or BackendLogrus. Defaults to BackendStd.
+	Backend string `json:"backend" yaml:"backend"`
+	// Verbosity is the maximum verbosity level. See verbosity.Level.
+	Verbosity int `json:"verbosity" yaml:"verbosity"`
+	// Output is OutputStdout, OutputStderr or the path of a file. Defaults to OutputStderr.
+	Output string `json:"output" yaml:"output"`
+	// Format is either FormatText or FormatJSON. Defaults to FormatText.
+	Format string `json:"format" yaml:"format"`
+	// Modules holds the per prefix verbosity overrides in the format accepted by verbosity.ParseModules, for example
+	// "db.*=4,http=2".
+	Modules string `json:"modules" yaml:"modules"`
+}
+
+// ParseJSON decodes and validates a JSON encoded Config. Unknown fields are rejected.
+func ParseJSON(data []byte) (Config, error) {
+	var c Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return c, fmt.Errorf("decode config: %w", err)
+	}
+
+	return c, c.Validate()
+}
+
+// ParseYAML decodes and validates a YAML encoded Config. Unknown fields are rejected.
+func ParseYAML(data []byte) (Config, error) {
+	var c Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil && err != io.EOF {
+		return c, fmt.Errorf("decode config: %w", err)
+	}
+
+	return c, c.Validate()
+}
+
+// Validate checks the configuration without opening the output. All problems found are reported joined into a single
+// error.
+func (c Config) Validate() error {
+	var errs []error
+	switch c.backend() {
+	case BackendStd, BackendZap, BackendZerolog, BackendLogrus:
+	default:
+		errs = append(errs, fmt.Errorf("invalid backend %q: expected one of %s, %s, %s or %s", c.Backend, BackendStd,
+			BackendZap, BackendZerolog, BackendLogrus))
+	}
+	if c.Verbosity < 0 {
+		errs = append(errs, fmt.Errorf("invalid verbosity %d: must not be negative", c.Verbosity))
+	}
+	switch c.format() {
+	case FormatText:
+	case FormatJSON:
+		if c.backend() == BackendStd {
+			errs = append(errs, fmt.Errorf("invalid format %q: not supported by backend %s", c.Format, BackendStd))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid format %q: expected %s or %s", c.Format, FormatText, FormatJSON))
+	}
+	if _, err := verbosity.ParseModules(c.Modules); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// New validates the configuration and creates the logger described by it.
+//
+// BackendStd writes using the standard flags and the prefix, followed by ": ", in front of the message. The other
+// backends use their default encoding for the format, with a timestamp added and colors disabled.
+//
+// If the output is a file, it is opened in append mode and created if missing. The returned function closes it and
+// must be called once the logger is no longer used. For stdout and stderr it does nothing.
+func New(c Config) (l logr.Logger, closeOutput func() error, err error) {
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+	modules, _ := verbosity.ParseModules(c.Modules)
+
+	w, closeOutput, err := c.open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v *verbosity.Level
+	switch c.backend() {
+	case BackendZap:
+		zl := logzap.New(c.Verbosity, zap.New(zapcore.NewCore(c.zapEncoder(), zapcore.AddSync(w), zap.DebugLevel)))
+		l, v = zl, zl.Verbosity()
+	case BackendZerolog:
+		zl := logzerolog.New(c.Verbosity, c.zerolog(w))
+		l, v = zl, zl.Verbosity()
+	case BackendLogrus:
+		ll := logrus.New(c.Verbosity, c.logrus(w))
+		l, v = ll, ll.Verbosity()
+	default:
+		sl := std.New(c.Verbosity, stdlog.New(w, "", stdlog.LstdFlags|stdlog.Lmsgprefix))
+		sl.SetPrefixTerminator(": ")
+		l, v = sl, sl.Verbosity()
+	}
+	v.SetModules(modules)
+
+	return l, closeOutput, nil
+}
+
+func (c Config) backend() string {
+	if c.Backend == "" {
+		return BackendStd
+	}
+
+	return strings.ToLower(c.Backend)
+}
+
+func (c Config) format() string {
+	if c.Format == "" {
+		return FormatText
+	}
+
+	return strings.ToLower(c.Format)
+}
+
+func (c Config) open() (io.Writer, func() error, error) {
+	switch c.Output {
+	case "", OutputStderr:
+		return os.Stderr, func() error { return nil }, nil
+	case OutputStdout:
+		return os.Stdout, func() error { return nil }, nil
+	}
+
+	f, err := os.OpenFile(c.Output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open output: %w", err)
+	}
+
+	return f, f.Close, nil
+}
+
+func (c Config) zapEncoder() zapcore.Encoder {
+	cfg := zap.NewProductionEncoderConfig()
+	if c.format() == FormatJSON {
+		return zapcore.NewJSONEncoder(cfg)
+	}
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	return zapcore.NewConsoleEncoder(cfg)
+}
+
+func (c Config) zerolog(w io.Writer) zerolog.Logger {
+	if c.format() == FormatText {
+		w = zerolog.ConsoleWriter{Out: w, NoColor: true}
+	}
+
+	return zerolog.New(w).With().Timestamp().Logger()
+}
+
+func (c Config) logrus(w io.Writer) *sirupsen.Logger {
+	ll := sirupsen.New()
+	ll.SetOutput(w)
+	ll.SetLevel(sirupsen.TraceLevel)
+	if c.format() == FormatJSON {
+		ll.SetFormatter(&sirupsen.JSONFormatter{})
+	} else {
+		ll.SetFormatter(&sirupsen.TextFormatter{DisableColors: true, FullTimestamp: true})
+	}
+
+	return ll
+}
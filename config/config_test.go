@@ -0,0 +1,175 @@
+package config_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/corvus-ch/logr/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Example() {
+	c, err := config.ParseYAML([]byte(`
+backend: std
+verbosity: 1
+output: stdout
+modules: db.*=4
+`))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%+v\n", c)
+	// Output:
+	// {Backend:std Verbosity:1 Output:stdout Format: Modules:db.*=4}
+}
+
+func Example_validation() {
+	_, err := config.ParseJSON([]byte(`{"backend": "std", "verbosity": -1, "format": "json", "modules": "db"}`))
+	fmt.Println(err)
+	// Output:
+	// invalid verbosity -1: must not be negative
+	// invalid format "json": not supported by backend std
+	// invalid module "db": expected pattern=verbosity
+}
+
+func TestParse(t *testing.T) {
+	want := config.Config{Backend: "zap", Verbosity: 2, Output: "/var/log/app.log", Format: "json", Modules: "db.*=4"}
+
+	c, err := config.ParseJSON([]byte(`{
+		"backend": "zap",
+		"verbosity": 2,
+		"output": "/var/log/app.log",
+		"format": "json",
+		"modules": "db.*=4"
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, want, c)
+
+	c, err = config.ParseYAML([]byte("backend: zap\nverbosity: 2\noutput: /var/log/app.log\nformat: json\nmodules: db.*=4\n"))
+	require.NoError(t, err)
+	assert.Equal(t, want, c)
+
+	c, err = config.ParseYAML(nil)
+	require.NoError(t, err)
+	assert.Equal(t, config.Config{}, c)
+
+	_, err = config.ParseJSON([]byte(`{"level":"debug"}`))
+	assert.EqualError(t, err, `decode config: json: unknown field "level"`)
+
+	_, err = config.ParseYAML([]byte("level: debug"))
+	assert.EqualError(t, err, "decode config: yaml: unmarshal errors:\n  line 1: field level not found in type config.Config")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		c    config.Config
+		err  string
+	}{
+		{"zero", config.Config{}, ""},
+		{"case insensitive", config.Config{Backend: "Zerolog", Format: "JSON"}, ""},
+		{"invalid backend", config.Config{Backend: "glog"}, `invalid backend "glog": expected one of std, zap, zerolog or logrus`},
+		{"invalid format", config.Config{Backend: "zap", Format: "xml"}, `invalid format "xml": expected text or json`},
+		{"invalid modules", config.Config{Modules: "db=high"}, `invalid module "db=high": verbosity must be an integer`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.c.Validate()
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		backend string
+		format  string
+		want    []string
+	}{
+		{"std", "text", []string{
+			" info message",
+			" error message",
+			" verbose message",
+			" db.pool: verbose db message",
+		}},
+		{"zap", "text", []string{
+			"info\tinfo message",
+			"error\terror message",
+			"debug\tverbose message",
+			"debug\tdb.pool\tverbose db message",
+		}},
+		{"zap", "json", []string{
+			`"msg":"info message"`,
+			`"msg":"error message"`,
+			`"msg":"verbose message","v":1`,
+			`"logger":"db.pool","msg":"verbose db message","v":4`,
+		}},
+		{"zerolog", "text", []string{
+			"INF info message",
+			"ERR error message",
+			"DBG verbose message v=1",
+			"DBG verbose db message prefix=db.pool v=4",
+		}},
+		{"zerolog", "json", []string{
+			`"message":"info message"`,
+			`"message":"error message"`,
+			`"level":"debug","v":1,`,
+			`"level":"debug","prefix":"db.pool","v":4,`,
+		}},
+		{"logrus", "text", []string{
+			`level=info msg="info message"`,
+			`level=error msg="error message"`,
+			`level=debug msg="verbose message" v=1`,
+			`level=debug msg="verbose db message" prefix=db.pool v=4`,
+		}},
+		{"logrus", "json", []string{
+			`"level":"info","msg":"info message"`,
+			`"level":"error","msg":"error message"`,
+			`"level":"debug","msg":"verbose message"`,
+			`"level":"debug","msg":"verbose db message","prefix":"db.pool"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backend+"/"+tt.format, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			l, closeOutput, err := config.New(config.Config{
+				Backend:   tt.backend,
+				Verbosity: 1,
+				Output:    path,
+				Format:    tt.format,
+				Modules:   "db.*=4",
+			})
+			require.NoError(t, err)
+			l.Info("info message")
+			l.Error("error message")
+			l.V(1).Info("verbose message")
+			l.V(2).Info("suppressed message")
+			db := l.NewWithPrefix("db").NewWithPrefix("pool")
+			db.V(4).Info("verbose db message")
+			db.V(5).Info("suppressed db message")
+			require.NoError(t, closeOutput())
+
+			out, err := os.ReadFile(path)
+			require.NoError(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, string(out), want)
+			}
+			assert.NotContains(t, string(out), "suppressed")
+		})
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	_, _, err := config.New(config.Config{Backend: "glog"})
+	assert.EqualError(t, err, `invalid backend "glog": expected one of std, zap, zerolog or logrus`)
+
+	_, _, err = config.New(config.Config{Output: filepath.Join(t.TempDir(), "missing", "app.log")})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
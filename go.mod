@@ -1,13 +1,22 @@
 module github.com/corvus-ch/logr
 
-go 1.16
+go 1.21
 
 require (
-	bou.ke/monkey v1.0.2
-	github.com/AlekSi/gocoverutil v0.2.0 // indirect
 	github.com/bketelsen/logr v0.0.0-20170116012416-f3d070bdd1c5
+	github.com/go-logr/logr v1.4.2
 	github.com/rs/zerolog v1.21.0
 	github.com/sirupsen/logrus v1.8.1
 	github.com/stretchr/testify v1.7.0
 	go.uber.org/zap v1.16.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/AlekSi/gocoverutil v0.2.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.uber.org/atomic v1.6.0 // indirect
+	go.uber.org/multierr v1.5.0 // indirect
+	golang.org/x/sys v0.0.0-20210119212857-b64e53b001e4 // indirect
 )
@@ -0,0 +1,134 @@
+// Package gologr bridges between logr.Logger by Tim Hockin and the github.com/go-logr/logr API.
+//
+// New turns a go-logr Logger into a logr.Logger, allowing code written against this project to log into sinks like
+// klog or controller-runtime. NewSink goes the other way and wraps any logr.Logger of this project as go-logr LogSink.
+package gologr
+
+import (
+	"fmt"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/internal/errs"
+	"github.com/corvus-ch/logr/verbosity"
+	golog "github.com/go-logr/logr"
+)
+
+// New creates a new instance of logr.Logger writing to a go-logr Logger.
+//
+// The verbosity v defines the upper limit. When creating a new sub logger using logr.Logger.V(), if the level passed
+// to V is greater than the verbosity, the sub logger will be silenced. The verbosity is shared by all loggers derived
+// from the returned logger and can be changed at runtime using Verbosity. The level is passed on to golog.Logger.V, so
+// the verbosity of the go-logr sink is honored too.
+//
+// NewWithPrefix is mapped to golog.Logger.WithName and WithValues or WithField to golog.Logger.WithValues. How nested
+// names are joined in the output is up to the go-logr sink. For the purpose of per prefix verbosity overrides, nested
+// prefixes are joined using the separator. The separator defaults to "." and can be changed using SetSeparator, which
+// should match the one used by the sink. A name already set on the golog.Logger passed to New is not part of the
+// prefix.
+func New(v int, l golog.Logger) *logger {
+	return &logger{
+		level:     0,
+		verbosity: verbosity.New(v),
+		separator: ".",
+		logger:    l.WithCallDepth(1),
+	}
+}
+
+type logger struct {
+	logr.Logger
+	level     int
+	verbosity *verbosity.Level
+	prefix    string
+	separator string
+	logger    golog.Logger
+}
+
+// Info implements logr.Logger.Info() by calling Info on the go-logr logger of the current level.
+func (l logger) Info(args ...interface{}) {
+	if l.Enabled() {
+		l.logger.V(l.level).Info(fmt.Sprint(args...))
+	}
+}
+
+// Infof implements logr.Logger.Infof() by calling Info on the go-logr logger of the current level.
+func (l logger) Infof(format string, args ...interface{}) {
+	if l.Enabled() {
+		l.logger.V(l.level).Info(fmt.Sprintf(format, args...))
+	}
+}
+
+// Enabled implements logr.Logger.Enabled() by checking if the current verbosity level is less or equal than the loggers
+// maximum verbosity for the loggers prefix (see Verbosity) and if the go-logr logger is enabled for that level.
+func (l logger) Enabled() bool {
+	return l.verbosity.EnabledFor(l.prefix, l.level) && l.logger.V(l.level).Enabled()
+}
+
+// Error implements logr.Logger.Error() by calling Error on the go-logr logger. The first argument being an error is
+// passed on as error value.
+func (l logger) Error(args ...interface{}) {
+	l.logger.Error(errs.Find(args), fmt.Sprint(args...))
+}
+
+// Errorf implements logr.Logger.Errorf() by calling Error on the go-logr logger. The first argument being an error is
+// passed on as error value.
+func (l logger) Errorf(format string, args ...interface{}) {
+	l.logger.Error(errs.Find(args), fmt.Sprintf(format, args...))
+}
+
+// Warn implements warn.Logger.Warn by calling Info on the go-logr logger of level zero, as go-logr has no warning
+// level. Like errors, warnings are written regardless of the level of the logger.
+func (l logger) Warn(args ...interface{}) {
+	l.logger.Info(fmt.Sprint(args...))
+}
+
+// Warnf implements warn.Logger.Warnf by calling Info on the go-logr logger of level zero, as go-logr has no warning
+// level. Like errors, warnings are written regardless of the level of the logger.
+func (l logger) Warnf(format string, args ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, args...))
+}
+
+// V implements logr.Logger.V.
+func (l logger) V(level int) logr.InfoLogger {
+	l.level = level
+	return l
+}
+
+// NewWithPrefix implements logr.Logger.NewWithPrefix by calling golog.Logger.WithName. How nested names are joined is
+// up to the go-logr sink.
+func (l logger) NewWithPrefix(prefix string) logr.Logger {
+	l.logger = l.logger.WithName(prefix)
+	if len(l.prefix) > 0 {
+		prefix = l.prefix + l.separator + prefix
+	}
+	l.prefix = prefix
+	return l
+}
+
+// WithValues returns a logger which adds the given key/value pairs using golog.Logger.WithValues.
+func (l logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	l.logger = l.logger.WithValues(keysAndValues...)
+	return l
+}
+
+// WithField implements logr.Logger.WithField by calling WithValues with a single key/value pair.
+func (l logger) WithField(name string, value interface{}) logr.Logger {
+	return l.WithValues(name, value)
+}
+
+// WithCallerSkip returns a logger which skips additional stack frames when determining the caller. Wrappers around the
+// logger use it to have the caller of the wrapper reported instead of the wrapper itself. It is mapped to
+// golog.Logger.WithCallDepth.
+func (l logger) WithCallerSkip(skip int) logr.Logger {
+	l.logger = l.logger.WithCallDepth(skip)
+	return l
+}
+
+// Verbosity returns the verbosity level shared by this logger and all loggers derived from it.
+func (l logger) Verbosity() *verbosity.Level {
+	return l.verbosity
+}
+
+// SetSeparator sets the separator used to join prefixes of nested calls to NewWithPrefix.
+func (l *logger) SetSeparator(sep string) {
+	l.separator = sep
+}
@@ -0,0 +1,117 @@
+package gologr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	stdlog "log"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/corvus-ch/logr/buffered"
+	"github.com/corvus-ch/logr/gologr"
+	test "github.com/corvus-ch/logr/internal"
+	"github.com/corvus-ch/logr/std"
+	"github.com/corvus-ch/logr/verbosity"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Example() {
+	fl := funcr.New(func(prefix, args string) {
+		fmt.Println(prefix, args)
+	}, funcr.Options{Verbosity: 1})
+	l := gologr.New(1, fl)
+	l.Info("Info level log message")
+	l.Infof("%X", "Info level log message printed in hex values")
+	l.Error("Error level log message")
+	l.Errorf("%X", "Error level log message printed in hex values")
+	l.NewWithPrefix("adipiscing").Info("This message has a name")
+	l.WithValues("user", "jane").Info("This message has a field")
+	l.V(1).Info("This message will be printed with verbose level")
+	l.V(2).Info("This message will not be printed as its verbosity exceeds the maximum")
+	// Output:
+	//  "level"=0 "msg"="Info level log message"
+	//  "level"=0 "msg"="496E666F206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573"
+	//  "msg"="Error level log message" "error"=null
+	//  "msg"="4572726F72206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573" "error"=null
+	// adipiscing "level"=0 "msg"="This message has a name"
+	//  "level"=0 "msg"="This message has a field" "user"="jane"
+	//  "level"=1 "msg"="This message will be printed with verbose level"
+}
+
+func Example_separator() {
+	fl := funcr.New(func(prefix, args string) {
+		fmt.Println(prefix, args)
+	}, funcr.Options{Verbosity: 2})
+	l := gologr.New(0, fl)
+	l.SetSeparator("/")
+	l.Verbosity().SetModules(verbosity.Modules{{Pattern: "db/*", Verbosity: 2}})
+	l.NewWithPrefix("db").NewWithPrefix("pool").V(2).Info("The prefix matches the name joined by funcr")
+	// Output:
+	// db/pool "level"=2 "msg"="The prefix matches the name joined by funcr"
+}
+
+func Example_sink() {
+	bl := buffered.New(1)
+	l := gologr.NewLogger(bl)
+	l.Info("Info level log message", "user", "jane")
+	l.Error(os.ErrNotExist, "Error level log message")
+	l.WithName("adipiscing").WithName("elit").Info("This message has a prefix")
+	l.V(1).Info("This message will be printed with verbose level")
+	l.V(2).Info("This message will not be printed as its verbosity exceeds the maximum")
+	bl.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO Info level log message user=jane
+	// ERROR Error level log message error="file does not exist"
+	// INFO adipiscing.elitThis message has a prefix
+	// V[1] This message will be printed with verbose level
+}
+
+func TestCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	want := test.Callers(gologr.New(1, funcr.NewJSON(func(obj string) {
+		buf.WriteString(obj + "\n")
+	}, funcr.Options{LogCaller: funcr.All, Verbosity: 1})))
+
+	var got []string
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var entry struct {
+			Caller struct {
+				File string
+				Line int
+			}
+		}
+		require.NoError(t, dec.Decode(&entry))
+		got = append(got, fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line))
+	}
+	test.AssertCallers(t, want, got)
+}
+
+func TestSinkCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := gologr.NewLogger(std.New(0, stdlog.New(buf, "", stdlog.Lshortfile)))
+	_, _, line, _ := runtime.Caller(0)
+	l.Info("Info level log message")
+	l.Error(nil, "Error level log message")
+	l.WithName("name").WithCallDepth(0).Info("Info level log message with a name")
+	want := fmt.Sprintf("logger_test.go:%d: Info level log message\n", line+1) +
+		fmt.Sprintf("logger_test.go:%d: Error level log message\n", line+2) +
+		fmt.Sprintf("namelogger_test.go:%d: Info level log message with a name\n", line+3)
+	assert.Equal(t, want, buf.String())
+}
+
+func Benchmark(b *testing.B) {
+	l := gologr.New(1, funcr.New(func(prefix, args string) {}, funcr.Options{Verbosity: 1}))
+	test.Benchmark(b, "error", l.Error)
+	test.Benchmarkf(b, "errorf", l.Errorf)
+	test.Benchmark(b, "info", l.Info)
+	test.Benchmarkf(b, "infof", l.Infof)
+	test.Benchmark(b, "v", l.V(1).Info)
+	test.Benchmarkf(b, "vf", l.V(1).Infof)
+	test.Benchmark(b, "disabled", l.V(2).Info)
+	test.Benchmarkf(b, "disabledf", l.V(2).Infof)
+}
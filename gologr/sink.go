@@ -0,0 +1,88 @@
+package gologr
+
+import (
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/internal/kv"
+	golog "github.com/go-logr/logr"
+)
+
+// NewSink creates a golog.LogSink writing to the given logr.Logger.
+//
+// The level passed to the sink is mapped to logr.Logger.V. Names added using golog.Logger.WithName are passed to
+// logr.Logger.NewWithPrefix, which joins nested names using the separator of the logger. Key/value pairs are added
+// using logr.Logger.WithField and errors passed to golog.Logger.Error are added as field named "error".
+//
+// If the logger has a WithCallerSkip method, like the loggers of this project, the frames of go-logr and the sink are
+// skipped, so the caller reported is the one of the golog.Logger method.
+func NewSink(l logr.Logger) golog.LogSink {
+	return &sink{logger: l}
+}
+
+// NewLogger is a shorthand for golog.New(NewSink(l)).
+func NewLogger(l logr.Logger) golog.Logger {
+	return golog.New(NewSink(l))
+}
+
+type sink struct {
+	logger logr.Logger
+}
+
+// callerSkipper is implemented by loggers able to skip additional stack frames when determining the caller.
+type callerSkipper interface {
+	WithCallerSkip(skip int) logr.Logger
+}
+
+// Init implements golog.LogSink.Init by skipping the frames of go-logr and the sink itself.
+func (s *sink) Init(info golog.RuntimeInfo) {
+	s.logger = withCallerSkip(s.logger, info.CallDepth+1)
+}
+
+// Enabled implements golog.LogSink.Enabled by checking if the logger is enabled for the given level.
+func (s *sink) Enabled(level int) bool {
+	return s.logger.V(level).Enabled()
+}
+
+// Info implements golog.LogSink.Info by calling logr.Logger.Info on the logger of the given level.
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	withValues(s.logger, keysAndValues).V(level).Info(msg)
+}
+
+// Error implements golog.LogSink.Error by calling logr.Logger.Error.
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	l := withValues(s.logger, keysAndValues)
+	if err != nil {
+		l = l.WithField("error", err)
+	}
+	l.Error(msg)
+}
+
+// WithValues implements golog.LogSink.WithValues.
+func (s *sink) WithValues(keysAndValues ...interface{}) golog.LogSink {
+	return &sink{logger: withValues(s.logger, keysAndValues)}
+}
+
+// WithName implements golog.LogSink.WithName.
+func (s *sink) WithName(name string) golog.LogSink {
+	return &sink{logger: s.logger.NewWithPrefix(name)}
+}
+
+// WithCallDepth implements golog.CallDepthLogSink.WithCallDepth.
+func (s *sink) WithCallDepth(depth int) golog.LogSink {
+	return &sink{logger: withCallerSkip(s.logger, depth)}
+}
+
+func withCallerSkip(l logr.Logger, skip int) logr.Logger {
+	if cs, ok := l.(callerSkipper); ok {
+		return cs.WithCallerSkip(skip)
+	}
+
+	return l
+}
+
+func withValues(l logr.Logger, keysAndValues []interface{}) logr.Logger {
+	for _, p := range kv.Pairs(keysAndValues...) {
+		l = l.WithField(p.Key, p.Value)
+	}
+
+	return l
+}
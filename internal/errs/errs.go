@@ -0,0 +1,125 @@
+// Package errs contains helpers to deal with error values passed to Error and Errorf.
+package errs
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Find returns the first argument implementing the error interface or nil if there is none.
+//
+// Typed nil pointers implementing error, like a nil *fs.PathError, are skipped. Calling their methods would panic and
+// fmt prints them as <nil>.
+func Find(args []interface{}) error {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok && !isNil(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isNil reports whether err is nil or an interface holding a nil pointer, map, slice, channel or function.
+func isNil(err error) bool {
+	if err == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(err); v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// Causes returns the messages of all errors wrapped by err. The message of err itself is not included.
+//
+// The tree of wrapped errors is walked depth first the same way errors.Is does. Besides errors.Unwrap, errors
+// implementing Unwrap() []error, as returned by errors.Join or fmt.Errorf with multiple %w verbs, are followed too.
+// Typed nil pointers are skipped the same way Find does.
+func Causes(err error) []string {
+	if isNil(err) {
+		return nil
+	}
+	var causes []string
+	for _, cause := range unwrap(err) {
+		causes = append(causes, cause.Error())
+		causes = append(causes, Causes(cause)...)
+	}
+
+	return causes
+}
+
+func unwrap(err error) []error {
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range u.Unwrap() {
+			if !isNil(e) {
+				errs = append(errs, e)
+			}
+		}
+		return errs
+	}
+	if e := errors.Unwrap(err); !isNil(e) {
+		return []error{e}
+	}
+
+	return nil
+}
+
+// Stack returns a stack trace of the calling goroutine. The argument skip is the number of stack frames to skip, with
+// 0 identifying the caller of Stack.
+//
+// Each frame is rendered as function name followed by an indented line containing file and line number.
+func Stack(skip int) string {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		sb.WriteString(frame.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(frame.File)
+		sb.WriteRune(':')
+		sb.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+		sb.WriteRune('\n')
+	}
+
+	return sb.String()
+}
+
+// Details renders the causes of err and the stack trace in a multi-line form meant to be read by humans. Each line
+// starts with a line break and is indented, so the result can be appended to the log message:
+//
+//	open config: open /etc/app.conf: file does not exist
+//	    caused by: open /etc/app.conf: file does not exist
+//	    caused by: file does not exist
+//	    stack:
+//	        main.main
+//	        	/app/main.go:42
+//
+// Both err and stack are optional. If there are neither causes nor a stack, an empty string is returned.
+func Details(err error, stack string) string {
+	var sb strings.Builder
+	if err != nil {
+		for _, cause := range Causes(err) {
+			sb.WriteString("\n    caused by: ")
+			sb.WriteString(cause)
+		}
+	}
+	if stack != "" {
+		sb.WriteString("\n    stack:\n        ")
+		sb.WriteString(strings.ReplaceAll(stack, "\n", "\n        "))
+	}
+
+	return sb.String()
+}
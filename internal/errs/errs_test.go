@@ -0,0 +1,53 @@
+package errs_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/corvus-ch/logr/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCauses(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist}
+	var nilErr *fs.PathError
+	tests := []struct {
+		name   string
+		err    error
+		causes []string
+	}{
+		{"nil", nil, nil},
+		{"plain", errors.New("plain"), nil},
+		{"typed nil", nilErr, nil},
+		{"wrapped typed nil", fmt.Errorf("read config: %w", nilErr), nil},
+		{"joined typed nil", errors.Join(nilErr, fs.ErrClosed), []string{"file already closed"}},
+		{"wrapped", fmt.Errorf("read config: %w", pathErr), []string{
+			"open /etc/app.conf: file does not exist",
+			"file does not exist",
+		}},
+		{"joined", errors.Join(pathErr, errors.New("timeout")), []string{
+			"open /etc/app.conf: file does not exist",
+			"file does not exist",
+			"timeout",
+		}},
+		{"multiple verbs", fmt.Errorf("start: %w, %w", fmt.Errorf("db: %w", fs.ErrClosed), fs.ErrPermission), []string{
+			"db: file already closed",
+			"file already closed",
+			"permission denied",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.causes, errs.Causes(tt.err))
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	var nilErr *fs.PathError
+	assert.Nil(t, errs.Find([]interface{}{"failed: ", nilErr}))
+	assert.Equal(t, fs.ErrNotExist, errs.Find([]interface{}{"failed: ", nilErr, fs.ErrNotExist}))
+	assert.Nil(t, errs.Find([]interface{}{"failed", 42}))
+}
@@ -0,0 +1,77 @@
+// Package kv contains helpers to deal with the key/value pairs passed to WithValues.
+package kv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Missing is used as the value of a key without a matching value.
+const Missing = "(MISSING)"
+
+// Pair is a single key/value pair.
+type Pair struct {
+	Key   string
+	Value interface{}
+}
+
+// Pairs converts a list of alternating keys and values into pairs.
+//
+// Keys not being a string are converted using fmt.Sprint. If the list has an odd length, the value of the last key is
+// set to Missing.
+func Pairs(keysAndValues ...interface{}) []Pair {
+	pairs := make([]Pair, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var value interface{} = Missing
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		pairs = append(pairs, Pair{key(keysAndValues[i]), value})
+	}
+
+	return pairs
+}
+
+// Append returns a new slice containing the pairs of both a and b.
+//
+// Unlike the builtin append, the backing array of a is never shared with the result. This allows loggers to safely
+// derive sub loggers from the same parent.
+func Append(a []Pair, b ...Pair) []Pair {
+	pairs := make([]Pair, 0, len(a)+len(b))
+	pairs = append(pairs, a...)
+	return append(pairs, b...)
+}
+
+// Format renders the pairs as space separated list of key=value. Values containing whitespace, quotes or an equal sign
+// are quoted.
+func Format(pairs []Pair) string {
+	var sb strings.Builder
+	for i, p := range pairs {
+		if i > 0 {
+			sb.WriteRune(' ')
+		}
+		sb.WriteString(p.Key)
+		sb.WriteRune('=')
+		sb.WriteString(value(p.Value))
+	}
+
+	return sb.String()
+}
+
+func key(k interface{}) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+
+	return fmt.Sprint(k)
+}
+
+func value(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
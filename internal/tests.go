@@ -1,7 +1,14 @@
 package internal
 
 import (
+	"fmt"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/warn"
+	"github.com/stretchr/testify/assert"
 )
 
 var (
@@ -30,3 +37,59 @@ func Benchmarkf(b *testing.B, name string, f func(string, ...interface{})) {
 		})
 	})
 }
+
+// Callers logs Msg using the methods of l, of sub loggers derived from it, of a wrapper using WithCallerSkip and using
+// the helpers of package warn. It returns the file and line each of the written messages is expected to report as
+// caller, in the form "file:line".
+func Callers(l logr.Logger) []string {
+	var callers []string
+	next := func() {
+		_, file, line, _ := runtime.Caller(1)
+		callers = append(callers, fmt.Sprintf("%s:%d", file, line+1))
+	}
+
+	next()
+	l.Info(Msg)
+	next()
+	l.Infof("%X", Msg)
+	next()
+	l.V(1).Info(Msg)
+	next()
+	l.NewWithPrefix("prefix").Info(Msg)
+	next()
+	l.Error(Msg)
+	next()
+	l.Errorf("%X", Msg)
+	next()
+	l.(warn.Logger).Warn(Msg)
+	next()
+	warn.Warnf(l, "%X", Msg)
+
+	w := l.(interface{ WithCallerSkip(int) logr.Logger }).WithCallerSkip(1)
+	next()
+	info(w, Msg)
+	next()
+	errorf(w, "%X", Msg)
+
+	return callers
+}
+
+// AssertCallers asserts each of the callers reported by a logger to match the one returned by Callers. The reported
+// caller may omit leading directories of the file.
+func AssertCallers(t *testing.T, want, got []string) {
+	t.Helper()
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i, c := range got {
+		assert.True(t, c == want[i] || strings.HasSuffix(want[i], "/"+c), "expected caller %s, got %s", want[i], c)
+	}
+}
+
+func info(l logr.Logger, args ...interface{}) {
+	l.Info(args...)
+}
+
+func errorf(l logr.Logger, format string, args ...interface{}) {
+	l.Errorf(format, args...)
+}
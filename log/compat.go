@@ -0,0 +1,139 @@
+package log
+
+import (
+	"io"
+	"log"
+	"strings"
+
+	"github.com/corvus-ch/logr/std"
+	"github.com/corvus-ch/logr/verbosity"
+	"github.com/corvus-ch/logr/writer_adapter"
+)
+
+// These flags are the ones of the standard library log package. See SetFlags for how they are applied.
+const (
+	Ldate         = log.Ldate
+	Ltime         = log.Ltime
+	Lmicroseconds = log.Lmicroseconds
+	Llongfile     = log.Llongfile
+	Lshortfile    = log.Lshortfile
+	LUTC          = log.LUTC
+	Lmsgprefix    = log.Lmsgprefix
+	LstdFlags     = log.LstdFlags
+)
+
+// Logger is the *log.Logger of the standard library, allowing code creating its own loggers to keep compiling after
+// switching the import to this package.
+type Logger = log.Logger
+
+// New calls log.New of the standard library.
+func New(out io.Writer, prefix string, flag int) *Logger {
+	return log.New(out, prefix, flag)
+}
+
+// stdLogger is implemented by loggers writing to *log.Logger instances, like std.Logger.
+type stdLogger interface {
+	Loggers() []*log.Logger
+}
+
+// verbosityLogger is implemented by all loggers of this project.
+type verbosityLogger interface {
+	Verbosity() *verbosity.Level
+}
+
+// SetOutput sets the output destination of the default logger.
+//
+// If the default logger writes to *log.Logger instances, like std.Logger, the output of all of them is changed. Any
+// other logger has no equivalent and is replaced by a std.Logger writing to w, using the flags set using SetFlags and
+// the verbosity of the replaced logger.
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	h := logger.Load()
+	if sl, ok := h.origin.(stdLogger); ok {
+		for _, l := range sl.Loggers() {
+			l.SetOutput(w)
+		}
+		return
+	}
+
+	v := 0
+	if vl, ok := h.origin.(verbosityLogger); ok {
+		v = vl.Verbosity().Get()
+	}
+	logger.Store(newHolder(std.New(v, log.New(w, "", h.flags)), h.prefix, h.flags))
+}
+
+// Flags returns the output flags of the default logger. See SetFlags.
+func Flags() int {
+	h := logger.Load()
+	if sl, ok := h.origin.(stdLogger); ok {
+		return sl.Loggers()[0].Flags()
+	}
+
+	return h.flags
+}
+
+// SetFlags sets the output flags of the default logger.
+//
+// If the default logger writes to *log.Logger instances, like std.Logger, the flags of all of them are changed. Any
+// other logger renders the output on its own. For those, the flags are only kept to be returned by Flags and to be used
+// by SetOutput.
+func SetFlags(flag int) {
+	mu.Lock()
+	defer mu.Unlock()
+	h := logger.Load()
+	if sl, ok := h.origin.(stdLogger); ok {
+		for _, l := range sl.Loggers() {
+			l.SetFlags(flag)
+		}
+		return
+	}
+
+	logger.Store(newHolder(h.origin, h.prefix, flag))
+}
+
+// Prefix returns the prefix set using SetPrefix.
+func Prefix() string {
+	return logger.Load().prefix
+}
+
+// SetPrefix sets the prefix of the default logger. It is emulated using logr.Logger.NewWithPrefix on the logger passed
+// to SetLogger, so each call replaces the previous prefix instead of nesting it. An empty prefix removes it.
+func SetPrefix(prefix string) {
+	mu.Lock()
+	defer mu.Unlock()
+	h := logger.Load()
+	logger.Store(newHolder(h.origin, prefix, h.flags))
+}
+
+// Output writes s using Info of the default logger. The calldepth has the same meaning as for log.Output: a value of 1
+// reports the caller of Output, if the logger supports it (see CallerSkipper). A trailing newline is removed. The
+// returned error is always nil, as logr.Logger does not report errors.
+func Output(calldepth int, s string) error {
+	withCallerSkip(logger.Load().base, calldepth).Info(strings.TrimSuffix(s, "\n"))
+	return nil
+}
+
+// Writer returns the output destination of the default logger.
+//
+// If the default logger writes to *log.Logger instances, like std.Logger, the writer of the first one is returned.
+// Otherwise, the returned writer writes to the default logger using info level.
+func Writer() io.Writer {
+	if sl, ok := logger.Load().origin.(stdLogger); ok {
+		return sl.Loggers()[0].Writer()
+	}
+
+	return writer_adapter.New(infoDefault)
+}
+
+// Default returns a *log.Logger writing to the default logger using info level. It is meant to be passed to code
+// requiring a *log.Logger. Any logger installed later using SetLogger is used as well.
+func Default() *Logger {
+	return log.New(writer_adapter.New(infoDefault), "", 0)
+}
+
+// infoDefault calls Info of the current default logger.
+func infoDefault(args ...interface{}) {
+	logger.Load().base.Info(args...)
+}
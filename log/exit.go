@@ -0,0 +1,101 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	exitMu    sync.RWMutex
+	exitFunc  = os.Exit
+	panicFunc = func(v interface{}) { panic(v) }
+	exitHooks []*exitHook
+)
+
+// exitHook wraps a function registered using OnExit, giving it an identity to be unregistered by.
+type exitHook struct {
+	f func()
+}
+
+// ExitError is the value passed to panic by PanicOnExit.
+type ExitError struct {
+	Code int
+}
+
+// Error implements the error interface.
+func (e ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// PanicOnExit can be passed to SetExitFunc to turn calls to Fatal, Fatalf and Fatalln into a panic with an ExitError.
+// This allows tests to recover from fatal paths and to inspect the exit code.
+func PanicOnExit(code int) {
+	panic(ExitError{code})
+}
+
+// SetExitFunc replaces the function called by Fatal, Fatalf and Fatalln after the message was logged and the exit hooks
+// did run. It returns the previous function. The default is os.Exit.
+//
+// If the function returns instead of terminating the program, Fatal, Fatalf and Fatalln return as well.
+func SetExitFunc(f func(code int)) func(code int) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	prev := exitFunc
+	exitFunc = f
+	return prev
+}
+
+// SetPanicFunc replaces the function called by Panic, Panicf and Panicln after the message was logged. It returns the
+// previous function. The default calls the builtin panic.
+func SetPanicFunc(f func(v interface{})) func(v interface{}) {
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	prev := panicFunc
+	panicFunc = f
+	return prev
+}
+
+// OnExit registers a function which gets called by Fatal, Fatalf and Fatalln before the exit function. It is meant to
+// flush buffered or asynchronous sinks, for example zap.Logger.Sync. Hooks are called in the order of registration.
+//
+// The returned function removes the hook again. It is safe to be called multiple times. In tests, pass it to
+// testing.T.Cleanup.
+func OnExit(f func()) (unregister func()) {
+	hook := &exitHook{f}
+	exitMu.Lock()
+	defer exitMu.Unlock()
+	exitHooks = append(exitHooks, hook)
+
+	return func() {
+		exitMu.Lock()
+		defer exitMu.Unlock()
+		hooks := make([]*exitHook, 0, len(exitHooks))
+		for _, h := range exitHooks {
+			if h != hook {
+				hooks = append(hooks, h)
+			}
+		}
+		exitHooks = hooks
+	}
+}
+
+func exit(code int) {
+	exitMu.RLock()
+	hooks := exitHooks
+	f := exitFunc
+	exitMu.RUnlock()
+
+	for _, hook := range hooks {
+		hook.f()
+	}
+	f(code)
+}
+
+func doPanic(v interface{}) {
+	exitMu.RLock()
+	f := panicFunc
+	exitMu.RUnlock()
+
+	f(v)
+}
@@ -1,121 +1,246 @@
 // Package log provides a global logger using logr.Logger.
 //
 // By default, it uses github.com/corvus-ch/std which is configured to write to STDERR.
+//
+// A logger can be attached to a context.Context using IntoContext. FromContext and the functions with the suffix Ctx
+// use that logger and fall back to the global one if the context does not carry a logger.
+//
+// Besides the functions of the standard library log package writing messages, the package also provides SetOutput,
+// SetFlags, SetPrefix and their counterparts, allowing it to be used as drop-in replacement. Concepts without an
+// equivalent in the installed logger are emulated as documented by the individual functions.
+//
+// Loggers implementing CallerSkipper report the caller of the functions of this package instead of this package. The
+// logrus implementation does so only if the hook returned by its NewCallerHook was added to the logrus.Logger.
+// Otherwise, logrus reports a function of the logrus adapter as caller.
 package log
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/bketelsen/logr"
 	"github.com/corvus-ch/logr/std"
+	"github.com/corvus-ch/logr/warn"
+)
+
+// CallerSkipper is implemented by loggers able to skip additional stack frames when determining the caller. All
+// loggers of this project implement it.
+type CallerSkipper interface {
+	// WithCallerSkip returns a logger which skips additional stack frames when determining the caller.
+	WithCallerSkip(skip int) logr.Logger
+}
+
+// holder wraps the logger, as atomic.Pointer requires a concrete type.
+type holder struct {
+	// origin is the logger as passed to SetLogger.
+	origin logr.Logger
+	// base is origin with the prefix set using SetPrefix applied.
+	base logr.Logger
+	// skipped is the logger used by the functions of this package. It skips the frame of the function, if supported.
+	skipped logr.Logger
+	prefix  string
+	// flags is only used if origin does not expose its *log.Logger instances.
+	flags int
+}
+
+func newHolder(origin logr.Logger, prefix string, flags int) *holder {
+	base := origin
+	if len(prefix) > 0 {
+		base = origin.NewWithPrefix(prefix)
+	}
+
+	return &holder{origin: origin, base: base, skipped: withCallerSkip(base, 1), prefix: prefix, flags: flags}
+}
+
+var (
+	logger atomic.Pointer[holder]
+	// mu serialises changes to the default logger, as some of them depend on the current one.
+	mu sync.Mutex
 )
 
-var logger logr.Logger
+type contextKey struct{}
 
 func init() {
-	l := std.New(0, log.New(os.Stderr, "", log.LstdFlags))
-	l.SetCallDepth(4)
-	SetLogger(l)
+	SetLogger(std.New(0, log.New(os.Stderr, "", log.LstdFlags)))
+}
+
+// SetLogger sets a new default logger and returns the previous one. The prefix set using SetPrefix is reset.
+//
+// It is safe to call SetLogger while other goroutines are logging.
+func SetLogger(l logr.Logger) logr.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	if prev := logger.Swap(newHolder(l, "", LstdFlags)); prev != nil {
+		return prev.origin
+	}
+
+	return nil
 }
 
-// SetLogger sets a new default logger.
-func SetLogger(l logr.Logger) {
-	logger = l
+// Replace sets a new default logger and returns a function restoring the previous one. It is meant to be used in tests:
+//
+//	t.Cleanup(log.Replace(buffered.New(0)))
+//
+// Restoring brings back the previous logger including the prefix and flags set using SetPrefix and SetFlags.
+func Replace(l logr.Logger) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := logger.Swap(newHolder(l, "", LstdFlags))
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		logger.Store(prev)
+	}
 }
 
 // Info calls Info() of the default logger.
 func Info(args ...interface{}) {
-	logger.Info(args...)
+	load().Info(args...)
 }
 
 // Infof calls Infof() of the default logger.
 func Infof(format string, args ...interface{}) {
-	logger.Infof(format, args...)
+	load().Infof(format, args...)
 }
 
 // Error calls Error() of the default logger.
 func Error(args ...interface{}) {
-	logger.Error(args...)
+	load().Error(args...)
 }
 
 // Errorf calls Errorf() the default logger.
 func Errorf(format string, args ...interface{}) {
-	logger.Errorf(format, args...)
+	load().Errorf(format, args...)
 }
 
 // V calls V() of the default logger.
 func V(level int) logr.InfoLogger {
-	l := logger.V(level)
-	sl, ok := l.(std.Logger)
-	if ok {
-		sl.SetCallDepth(2)
-		return sl
-	}
-
-	return l
+	return logger.Load().base.V(level)
 }
 
 // NewWithPrefix calls NewWithPrefix() of the default logger.
 func NewWithPrefix(prefix string) logr.Logger {
-	l := logger.NewWithPrefix(prefix)
-	sl, ok := l.(std.Logger)
-	if ok {
-		sl.SetCallDepth(2)
-		return sl
+	return logger.Load().base.NewWithPrefix(prefix)
+}
+
+// IntoContext returns a copy of ctx carrying the given logger.
+func IntoContext(ctx context.Context, l logr.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx. If ctx does not carry a logger, the default logger is returned.
+func FromContext(ctx context.Context) logr.Logger {
+	if l, ok := ctx.Value(contextKey{}).(logr.Logger); ok {
+		return l
 	}
 
-	return l
+	return logger.Load().base
+}
+
+// InfoCtx calls Info() of the logger carried by ctx or of the default logger.
+func InfoCtx(ctx context.Context, args ...interface{}) {
+	fromContext(ctx).Info(args...)
+}
+
+// InfofCtx calls Infof() of the logger carried by ctx or of the default logger.
+func InfofCtx(ctx context.Context, format string, args ...interface{}) {
+	fromContext(ctx).Infof(format, args...)
+}
+
+// ErrorCtx calls Error() of the logger carried by ctx or of the default logger.
+func ErrorCtx(ctx context.Context, args ...interface{}) {
+	fromContext(ctx).Error(args...)
+}
+
+// ErrorfCtx calls Errorf() of the logger carried by ctx or of the default logger.
+func ErrorfCtx(ctx context.Context, format string, args ...interface{}) {
+	fromContext(ctx).Errorf(format, args...)
+}
+
+// Warn calls Warn() of the default logger or Info() if it does not implement warn.Logger.
+func Warn(args ...interface{}) {
+	warn.Warn(load(), args...)
+}
+
+// Warnf calls Warnf() of the default logger or Infof() if it does not implement warn.Logger.
+func Warnf(format string, args ...interface{}) {
+	warn.Warnf(load(), format, args...)
 }
 
 // Print is equivalent to Info()
 func Print(args ...interface{}) {
-	logger.Info(args...)
+	load().Info(args...)
 }
 
 // Printf is equivalent to Infof()
 func Printf(format string, args ...interface{}) {
-	logger.Infof(format, args...)
+	load().Infof(format, args...)
 }
 
 // Println is equivalent to Info()
 func Println(args ...interface{}) {
-	logger.Info(args...)
+	load().Info(args...)
 }
 
-// Fatal is equivalent to Error() followed by a call to os.Exit(1).
+// Fatal is equivalent to Error() followed by a call to os.Exit(1). See SetExitFunc and OnExit to customise the exit.
 func Fatal(args ...interface{}) {
-	logger.Error(args...)
-	os.Exit(1)
+	load().Error(args...)
+	exit(1)
 }
 
-// Fatalf is equivalent to Errorf() followed by a call to os.Exit(1).
+// Fatalf is equivalent to Errorf() followed by a call to os.Exit(1). See SetExitFunc and OnExit to customise the exit.
 func Fatalf(format string, args ...interface{}) {
-	logger.Errorf(format, args...)
-	os.Exit(1)
+	load().Errorf(format, args...)
+	exit(1)
 }
 
-// Fatalln is equivalent to Error() followed by a call to os.Exit(1).
+// Fatalln is equivalent to Error() followed by a call to os.Exit(1). See SetExitFunc and OnExit to customise the exit.
 func Fatalln(args ...interface{}) {
-	logger.Error(args...)
-	os.Exit(1)
+	load().Error(args...)
+	exit(1)
 }
 
-// Panic is equivalent to Error() followed by a call to panic().
+// Panic is equivalent to Error() followed by a call to panic(). See SetPanicFunc to customise the panic.
 func Panic(args ...interface{}) {
-	logger.Error(args...)
-	panic(fmt.Sprint(args...))
+	load().Error(args...)
+	doPanic(fmt.Sprint(args...))
 }
 
-// Panicf is equivalent to Errorf() followed by a call to panic().
+// Panicf is equivalent to Errorf() followed by a call to panic(). See SetPanicFunc to customise the panic.
 func Panicf(format string, args ...interface{}) {
-	logger.Errorf(format, args...)
-	panic(fmt.Sprintf(format, args...))
+	load().Errorf(format, args...)
+	doPanic(fmt.Sprintf(format, args...))
 }
 
-// Panicln is equivalent to Error() followed by a call to panic().
+// Panicln is equivalent to Error() followed by a call to panic(). See SetPanicFunc to customise the panic.
 func Panicln(args ...interface{}) {
-	logger.Error(args...)
-	panic(fmt.Sprint(args...))
+	load().Error(args...)
+	doPanic(fmt.Sprint(args...))
+}
+
+// fromContext returns the logger carried by ctx or the default logger. Unlike FromContext, the frame of the function
+// of this package is skipped, as the result is used from within this package.
+func fromContext(ctx context.Context) logr.Logger {
+	if l, ok := ctx.Value(contextKey{}).(logr.Logger); ok {
+		return withCallerSkip(l, 1)
+	}
+
+	return load()
+}
+
+// load returns the default logger to be used by the functions of this package.
+func load() logr.Logger {
+	return logger.Load().skipped
+}
+
+func withCallerSkip(l logr.Logger, skip int) logr.Logger {
+	if cs, ok := l.(CallerSkipper); ok {
+		return cs.WithCallerSkip(skip)
+	}
+
+	return l
 }
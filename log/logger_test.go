@@ -2,17 +2,22 @@ package log_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	stdlog "log"
 	"os"
+	"runtime"
+	"sync"
 	"testing"
 
-	"bou.ke/monkey"
 	"github.com/corvus-ch/logr/buffered"
 	test "github.com/corvus-ch/logr/internal"
 	"github.com/corvus-ch/logr/log"
 	"github.com/corvus-ch/logr/std"
+	logzap "github.com/corvus-ch/logr/zap"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func Example() {
@@ -45,15 +50,30 @@ func Example() {
 
 func Example_callDepth() {
 	l := std.New(1, stdlog.New(os.Stdout, "", stdlog.Lshortfile))
-	l.SetCallDepth(3)
 	log.SetLogger(l)
 	log.Error("Cras justo odio, dapibus ac facilisis.")
 	log.NewWithPrefix("adipiscing").Info("Cras justo odio, dapibus ac facilisis.")
 	log.V(1).Info("Cras justo odio, dapibus ac facilisis.")
 	// Output:
-	// logger_test.go:50: Cras justo odio, dapibus ac facilisis.
-	// adipiscinglogger_test.go:51: Cras justo odio, dapibus ac facilisis.
-	// logger_test.go:52: Cras justo odio, dapibus ac facilisis.
+	// logger_test.go:54: Cras justo odio, dapibus ac facilisis.
+	// adipiscinglogger_test.go:55: Cras justo odio, dapibus ac facilisis.
+	// logger_test.go:56: Cras justo odio, dapibus ac facilisis.
+}
+
+func Example_context() {
+	l := buffered.New(0)
+	log.SetLogger(l)
+	ctx := log.IntoContext(context.Background(), l.NewWithPrefix("request: ").WithField("id", 42))
+	log.InfoCtx(ctx, "Info level log message")
+	log.ErrorfCtx(ctx, "%X", "Error level log message printed in hex values")
+	log.FromContext(ctx).Info("Using the logger carried by the context directly")
+	log.InfoCtx(context.Background(), "Falling back to the default logger")
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO request: Info level log message id=42
+	// ERROR request: 4572726F72206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573 id=42
+	// INFO request: Using the logger carried by the context directly id=42
+	// INFO Falling back to the default logger
 }
 
 func setup() *bytes.Buffer {
@@ -62,7 +82,7 @@ func setup() *bytes.Buffer {
 	return l.Buf()
 }
 
-func testPanic(t *testing.T, name, panic, out string, f func()) {
+func testPanic(t *testing.T, name string, panic interface{}, out string, f func()) {
 	t.Run(name, func(t *testing.T) {
 		buf := setup()
 		assert.PanicsWithValue(t, panic, f)
@@ -70,19 +90,101 @@ func testPanic(t *testing.T, name, panic, out string, f func()) {
 	})
 }
 
+func TestSetLogger(t *testing.T) {
+	l1 := buffered.New(0)
+	l2 := buffered.New(0)
+	t.Cleanup(log.Replace(l1))
+	assert.Equal(t, l1, log.SetLogger(l2))
+	log.Info("Info level log message")
+	assert.Equal(t, "", l1.Buf().String())
+	assert.Equal(t, "INFO Info level log message\n", l2.Buf().String())
+}
+
+func TestSetLogger_concurrent(t *testing.T) {
+	defer log.Replace(buffered.New(0))()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				log.Info(test.Msg)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				log.SetLogger(buffered.New(0))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestReplace(t *testing.T) {
+	l1 := buffered.New(0)
+	l2 := buffered.New(0)
+	t.Cleanup(log.Replace(l1))
+	restore := log.Replace(l2)
+	log.Info("Written to the replacement")
+	restore()
+	log.Info("Written to the original")
+	assert.Equal(t, "INFO Written to the original\n", l1.Buf().String())
+	assert.Equal(t, "INFO Written to the replacement\n", l2.Buf().String())
+}
+
+func TestReplace_keepsPrefix(t *testing.T) {
+	l := buffered.New(0)
+	t.Cleanup(log.Replace(l))
+	log.SetPrefix("app: ")
+	log.SetFlags(log.Lshortfile)
+	restore := log.Replace(buffered.New(0))
+	assert.Equal(t, "", log.Prefix())
+	restore()
+	assert.Equal(t, "app: ", log.Prefix())
+	assert.Equal(t, log.Lshortfile, log.Flags())
+	log.Print("Written with the restored prefix")
+	assert.Equal(t, "INFO app: Written with the restored prefix\n", l.Buf().String())
+}
+
 func TestFatal(t *testing.T) {
-	defer monkey.Patch(os.Exit, func(code int) { panic(fmt.Sprintf("exit status %d", code)) }).Unpatch()
-	testPanic(t, "fatal", "exit status 1", test.Msg, func() {
+	defer log.SetExitFunc(log.SetExitFunc(log.PanicOnExit))
+	testPanic(t, "fatal", log.ExitError{Code: 1}, test.Msg, func() {
 		log.Fatal(test.Msg)
 	})
-	testPanic(t, "fatalf", "exit status 1", test.Formatted, func() {
+	testPanic(t, "fatalf", log.ExitError{Code: 1}, test.Formatted, func() {
 		log.Fatalf("%X", test.Msg)
 	})
-	testPanic(t, "fatalln", "exit status 1", test.Msg, func() {
+	testPanic(t, "fatalln", log.ExitError{Code: 1}, test.Msg, func() {
 		log.Fatalln(test.Msg)
 	})
 }
 
+func TestOnExit(t *testing.T) {
+	var calls []string
+	t.Cleanup(log.OnExit(func() { calls = append(calls, "first hook") }))
+	t.Cleanup(log.OnExit(func() { calls = append(calls, "second hook") }))
+	unregister := log.OnExit(func() { calls = append(calls, "removed hook") })
+	unregister()
+	unregister()
+	defer log.SetExitFunc(log.SetExitFunc(func(code int) {
+		calls = append(calls, fmt.Sprintf("exit %d", code))
+	}))
+	buf := setup()
+	log.Fatal(test.Msg)
+	assert.Equal(t, []string{"first hook", "second hook", "exit 1"}, calls)
+	assert.Equal(t, fmt.Sprintf("ERROR %s\n", test.Msg), buf.String())
+}
+
+func TestSetPanicFunc(t *testing.T) {
+	var v interface{}
+	defer log.SetPanicFunc(log.SetPanicFunc(func(p interface{}) { v = p }))
+	buf := setup()
+	log.Panicf("%X", test.Msg)
+	assert.Equal(t, test.Formatted, v)
+	assert.Equal(t, fmt.Sprintf("ERROR %s\n", test.Formatted), buf.String())
+}
+
 func TestPanic(t *testing.T) {
 	testPanic(t, "panic", test.Msg, test.Msg, func() {
 		log.Panic(test.Msg)
@@ -94,3 +196,65 @@ func TestPanic(t *testing.T) {
 		log.Panicln(test.Msg)
 	})
 }
+
+func TestCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	encoderCfg := zapcore.EncoderConfig{CallerKey: "caller", EncodeCaller: zapcore.ShortCallerEncoder}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), zapcore.AddSync(buf), zap.DebugLevel)
+	defer log.Replace(logzap.New(1, zap.New(core, zap.AddCaller())))()
+	ctx := log.IntoContext(context.Background(), log.NewWithPrefix("context"))
+
+	_, _, line, _ := runtime.Caller(0)
+	log.Info(test.Msg)
+	log.Errorf("%X", test.Msg)
+	log.V(1).Info(test.Msg)
+	log.NewWithPrefix("prefix").Info(test.Msg)
+	log.FromContext(ctx).Info(test.Msg)
+	log.InfoCtx(ctx, test.Msg)
+	log.ErrorCtx(context.Background(), test.Msg)
+	log.Print(test.Msg)
+	log.Output(1, test.Msg)
+	log.Warn(test.Msg)
+
+	var want string
+	for i := 1; i <= 10; i++ {
+		var v string
+		if i == 3 {
+			v = `,"v":1`
+		}
+		want += fmt.Sprintf("{\"caller\":\"log/logger_test.go:%d\"%s}\n", line+i, v)
+	}
+	assert.Equal(t, want, buf.String())
+}
+
+func Example_compatibility() {
+	defer log.Replace(std.New(0, stdlog.New(os.Stderr, "", stdlog.LstdFlags)))()
+	log.SetOutput(os.Stdout)
+	log.SetFlags(log.Lmsgprefix)
+	log.SetPrefix("app: ")
+	log.Print("Written to the *log.Logger of the std logger")
+	log.Output(1, "Written using Output\n")
+	log.Default().Print("Written using the *log.Logger returned by Default")
+	fmt.Printf("%q %t\n", log.Prefix(), log.Flags() == log.Lmsgprefix)
+	// Output:
+	// app: Written to the *log.Logger of the std logger
+	// app: Written using Output
+	// app: Written using the *log.Logger returned by Default
+	// "app: " true
+}
+
+func Example_compatibilityEmulated() {
+	l := buffered.New(0)
+	defer log.Replace(l)()
+	log.SetPrefix("app: ")
+	log.SetFlags(0)
+	log.Print("Written to the buffered logger")
+	fmt.Fprintln(log.Writer(), "Written using the writer")
+	l.Buf().WriteTo(os.Stdout)
+	log.SetOutput(os.Stdout)
+	log.Print("Written to a std logger replacing the buffered logger")
+	// Output:
+	// INFO app: Written to the buffered logger
+	// INFO app: Written using the writer
+	// app: Written to a std logger replacing the buffered logger
+}
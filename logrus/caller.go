@@ -0,0 +1,94 @@
+package logrus
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// callerSkipKey is the context key under which entries created by this package carry the number of additional stack
+// frames to skip.
+type callerSkipKey struct{}
+
+// callerSkipContext returns a context carrying the number of additional stack frames to skip.
+func callerSkipContext(skip int) context.Context {
+	return context.WithValue(context.Background(), callerSkipKey{}, skip)
+}
+
+// maxCallerDepth limits the number of stack frames inspected when searching the caller.
+const maxCallerDepth = 32
+
+var (
+	logrusPackage = reflect.TypeOf(logrus.Entry{}).PkgPath() + "."
+	ownPackage    = reflect.TypeOf(logger{}).PkgPath() + "."
+)
+
+// callerHook replaces the caller determined by logrus, which is a function of this package, with the caller of the
+// logr.Logger method. Entries not created by this package are left untouched.
+type callerHook struct{}
+
+// NewCallerHook returns a logrus.Hook correcting the caller reported by logrus.Logger.ReportCaller.
+//
+// As logrus reports the first caller outside of its own package, entries written through the logr.Logger returned by
+// New report a function of this package as their caller. Add the hook using logrus.Logger.AddHook to have the caller of
+// the logr.Logger method reported instead, honouring WithCallerSkip:
+//
+//	ll := logrus.New()
+//	ll.SetReportCaller(true)
+//	ll.AddHook(log.NewCallerHook())
+//	l := log.New(1, ll)
+//
+// Entries not written through this package are left untouched. Adding the hook more than once is harmless but wasteful.
+func NewCallerHook() logrus.Hook {
+	return callerHook{}
+}
+
+// Levels implements logrus.Hook.Levels by returning all levels.
+func (callerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.Fire.
+func (callerHook) Fire(entry *logrus.Entry) error {
+	if entry.Caller == nil || entry.Context == nil {
+		return nil
+	}
+	skip, ok := entry.Context.Value(callerSkipKey{}).(int)
+	if !ok {
+		return nil
+	}
+	if frame, ok := caller(skip); ok {
+		entry.Caller = &frame
+	}
+
+	return nil
+}
+
+// caller returns the first stack frame outside of logrus and this package, skipping additional skip frames.
+func caller(skip int) (runtime.Frame, bool) {
+	pcs := make([]uintptr, maxCallerDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	inside := true
+	for {
+		frame, more := frames.Next()
+		if inside && !isInternal(frame.Function) {
+			inside = false
+		}
+		if !inside {
+			if skip == 0 {
+				return frame, true
+			}
+			skip--
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+func isInternal(function string) bool {
+	return strings.HasPrefix(function, logrusPackage) || strings.HasPrefix(function, ownPackage)
+}
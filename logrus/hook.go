@@ -0,0 +1,89 @@
+package logrus
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/warn"
+	"github.com/sirupsen/logrus"
+)
+
+// NewHook creates a logrus.Hook writing every entry to the given logr.Logger.
+//
+// Entries with logrus.PanicLevel, logrus.FatalLevel or logrus.ErrorLevel are written using logr.Logger.Error. Entries
+// with logrus.WarnLevel are written using warn.Warn and entries with logrus.InfoLevel using logr.Logger.Info. Entries
+// with logrus.DebugLevel are written using V(1) and entries with logrus.TraceLevel using V(2).
+//
+// A field named "prefix", as written by the logger returned by New, is passed to logr.Logger.NewWithPrefix. All other
+// fields are added using logr.Logger.WithField, sorted by their key.
+//
+// Hooks only receive entries enabled by the level of the logrus.Logger. Use Redirect to forward all entries.
+func NewHook(l logr.Logger) logrus.Hook {
+	return &hook{logger: l}
+}
+
+// Redirect forwards all entries of the logrus.Logger to the given logr.Logger using a hook created by NewHook. The
+// level of the logrus.Logger is set to logrus.TraceLevel, leaving the decision about which entries to write to the
+// logr.Logger. The output of the logrus.Logger itself is discarded.
+func Redirect(ll *logrus.Logger, l logr.Logger) {
+	ll.AddHook(NewHook(l))
+	ll.SetLevel(logrus.TraceLevel)
+	Discard(ll)
+}
+
+// Discard discards the output of the logrus.Logger, leaving only its hooks to act on entries. Unlike setting the output
+// to io.Discard, entries are not formatted either.
+func Discard(ll *logrus.Logger) {
+	ll.SetOutput(io.Discard)
+	ll.SetFormatter(discardFormatter{})
+}
+
+type hook struct {
+	logger logr.Logger
+}
+
+// Levels implements logrus.Hook.Levels by returning all levels.
+func (h *hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.Fire.
+func (h *hook) Fire(entry *logrus.Entry) error {
+	l := h.logger
+	if prefix, ok := entry.Data["prefix"]; ok {
+		l = l.NewWithPrefix(fmt.Sprint(prefix))
+	}
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		if k != "prefix" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		l = l.WithField(k, entry.Data[k])
+	}
+
+	switch {
+	case entry.Level <= logrus.ErrorLevel:
+		l.Error(entry.Message)
+	case entry.Level == logrus.WarnLevel:
+		warn.Warn(l, entry.Message)
+	case entry.Level == logrus.InfoLevel:
+		l.Info(entry.Message)
+	default:
+		l.V(int(entry.Level - logrus.InfoLevel)).Info(entry.Message)
+	}
+
+	return nil
+}
+
+// discardFormatter is a logrus.Formatter producing no output.
+type discardFormatter struct{}
+
+// Format implements logrus.Formatter.Format.
+func (discardFormatter) Format(*logrus.Entry) ([]byte, error) {
+	return nil, nil
+}
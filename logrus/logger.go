@@ -2,46 +2,92 @@
 package logrus
 
 import (
+	"context"
+
 	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/internal/errs"
+	"github.com/corvus-ch/logr/internal/kv"
+	"github.com/corvus-ch/logr/verbosity"
 	"github.com/sirupsen/logrus"
 )
 
 // New creates a new instance of logr.Logger.
-func New(verbosity int, l *logrus.Logger) *logger {
+//
+// NewWithPrefix adds a field named "prefix". Calling NewWithPrefix on a logger which already has a prefix, joins both
+// prefixes using the separator. The separator defaults to "." and can be changed using SetSeparator.
+//
+// If one of the arguments passed to Error or Errorf is an error, it is added as field using logrus.Entry.WithError. The
+// messages of errors wrapped by it are added as field named "errorCauses". If enabled using SetStackTrace, the stack
+// trace of the call to Error or Errorf is added as field named "stack".
+//
+// Info is written with logrus.InfoLevel. Sub loggers created using V(n) with n greater than zero write with the level
+// returned by the level mapper, which defaults to DebugLevels and can be changed using SetLevelMapper. Their entries
+// carry the field "v" holding n, allowing to filter on the exact verbosity.
+//
+// The logrus.Logger is not modified. As logrus reports the first caller outside of its own package, a function of this
+// package is reported as caller if logrus.Logger.ReportCaller is enabled. Add the hook returned by NewCallerHook to
+// report the caller of the logr.Logger method instead.
+func New(v int, l *logrus.Logger) *logger {
 	return &logger{
 		level:     0,
-		verbosity: verbosity,
+		verbosity: verbosity.New(v),
 		prefix:    "",
+		separator: ".",
+		mapper:    DebugLevels,
 		logger:    l,
+		ctx:       callerSkipContext(0),
+	}
+}
+
+// DebugLevels maps all verbosity levels to logrus.DebugLevel.
+func DebugLevels(v int) logrus.Level {
+	return logrus.DebugLevel
+}
+
+// TraceLevels maps V(1) to logrus.DebugLevel and any further verbosity level to logrus.TraceLevel.
+func TraceLevels(v int) logrus.Level {
+	if v > 1 {
+		return logrus.TraceLevel
 	}
+
+	return logrus.DebugLevel
 }
 
 type logger struct {
 	logr.Logger
 	level     int
-	verbosity int
+	verbosity *verbosity.Level
 	prefix    string
+	separator string
+	stack     bool
+	fields    logrus.Fields
+	mapper    func(v int) logrus.Level
 	logger    *logrus.Logger
+	// callerSkip is the number of additional stack frames skipped when determining the caller.
+	callerSkip int
+	// ctx carries callerSkip to the caller hook. It is created whenever callerSkip changes, so writing an entry does not
+	// need to allocate it.
+	ctx context.Context
 }
 
-// Info implements logr.Logger.Info() by writing an event with info level or debug level in case a sub logger was
-// created using V() with level greater than zero.
+// Info implements logr.Logger.Info() by writing an event with info level or the level returned by the level mapper in
+// case a sub logger was created using V() with level greater than zero.
 func (l logger) Info(args ...interface{}) {
 	if l.Enabled() {
 		if l.level > 0 {
-			l.entry().Debug(args...)
+			l.entry().WithField("v", l.level).Log(l.mapper(l.level), args...)
 		} else {
 			l.entry().Info(args...)
 		}
 	}
 }
 
-// Infof implements logr.Logger.Infof() by writing an event with info level or debug level in case a sub logger was
-// created using V() with level greater than zero.
+// Infof implements logr.Logger.Infof() by writing an event with info level or the level returned by the level mapper in
+// case a sub logger was created using V() with level greater than zero.
 func (l logger) Infof(format string, args ...interface{}) {
 	if l.Enabled() {
 		if l.level > 0 {
-			l.entry().Debugf(format, args...)
+			l.entry().WithField("v", l.level).Logf(l.mapper(l.level), format, args...)
 		} else {
 			l.entry().Infof(format, args...)
 		}
@@ -49,45 +95,120 @@ func (l logger) Infof(format string, args ...interface{}) {
 }
 
 // Enabled implements logr.Logger.Enabled() by checking if the current verbosity level is less or equal than the loggers
-// maximum verbosity.
+// maximum verbosity for the loggers prefix. The maximum verbosity can be changed at any time using Verbosity, including
+// per prefix overrides.
 func (l logger) Enabled() bool {
-	return l.level <= l.verbosity
+	return l.verbosity.EnabledFor(l.prefix, l.level)
 }
 
 // Error implements logr.Logger.Error() by writing an event with error level.
 func (l logger) Error(args ...interface{}) {
-	l.entry().Error(args...)
+	l.errorEntry(errs.Find(args)).Error(args...)
 }
 
 // Errorf implements logr.Logger.Errorf() by writing an event with error level.
 func (l logger) Errorf(format string, args ...interface{}) {
-	l.entry().Errorf(format, args...)
+	l.errorEntry(errs.Find(args)).Errorf(format, args...)
+}
+
+// Warn implements warn.Logger.Warn by writing an event with warn level.
+func (l logger) Warn(args ...interface{}) {
+	l.entry().Warn(args...)
+}
+
+// Warnf implements warn.Logger.Warnf by writing an event with warn level.
+func (l logger) Warnf(format string, args ...interface{}) {
+	l.entry().Warnf(format, args...)
 }
 
 // V implements logr.Logger.V.
 func (l logger) V(level int) logr.InfoLogger {
-	return &logger{
-		level:     level,
-		verbosity: l.verbosity,
-		prefix:    l.prefix,
-		logger:    l.logger,
-	}
+	l.level = level
+	return &l
 }
 
-// NewWithPrefix implements logr.Logger.NewWithPrefix by adding a field named "prefix".
+// NewWithPrefix implements logr.Logger.NewWithPrefix by joining the prefix with the current one and setting it as
+// field named "prefix".
 func (l logger) NewWithPrefix(prefix string) logr.Logger {
-	return &logger{
-		level:     l.level,
-		verbosity: l.verbosity,
-		prefix:    prefix,
-		logger:    l.logger,
+	if len(l.prefix) > 0 {
+		prefix = l.prefix + l.separator + prefix
+	}
+	l.prefix = prefix
+	return &l
+}
+
+// WithValues returns a logger which adds the given key/value pairs as fields using logrus.Entry.WithFields.
+func (l logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	fields := make(logrus.Fields, len(l.fields)+len(keysAndValues)/2)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	for _, p := range kv.Pairs(keysAndValues...) {
+		fields[p.Key] = p.Value
 	}
+	l.fields = fields
+	return &l
 }
 
+// WithField implements logr.Logger.WithField by calling WithValues with a single key/value pair.
+func (l logger) WithField(name string, value interface{}) logr.Logger {
+	return l.WithValues(name, value)
+}
+
+// WithCallerSkip returns a logger which skips additional stack frames when determining the caller. Wrappers around the
+// logger use it to have the caller of the wrapper reported instead of the wrapper itself.
+func (l logger) WithCallerSkip(skip int) logr.Logger {
+	l.callerSkip += skip
+	l.ctx = callerSkipContext(l.callerSkip)
+	return &l
+}
+
+// Verbosity returns the verbosity level shared by this logger and all loggers derived from it.
+func (l logger) Verbosity() *verbosity.Level {
+	return l.verbosity
+}
+
+// SetStackTrace enables or disables capturing the stack trace on calls to Error and Errorf.
+func (l *logger) SetStackTrace(enabled bool) {
+	l.stack = enabled
+}
+
+// SetLevelMapper sets the function mapping the verbosity level of sub loggers created using V(n) with n greater than
+// zero to the logrus level. See DebugLevels and TraceLevels.
+func (l *logger) SetLevelMapper(mapper func(v int) logrus.Level) {
+	l.mapper = mapper
+}
+
+// SetSeparator sets the separator used to join prefixes of nested calls to NewWithPrefix.
+func (l *logger) SetSeparator(sep string) {
+	l.separator = sep
+}
+
+func (l logger) errorEntry(err error) *logrus.Entry {
+	entry := l.entry()
+	if err != nil {
+		entry = entry.WithError(err)
+		if causes := errs.Causes(err); len(causes) > 0 {
+			entry = entry.WithField("errorCauses", causes)
+		}
+	}
+	if l.stack {
+		entry = entry.WithField("stack", errs.Stack(2+l.callerSkip))
+	}
+
+	return entry
+}
+
+// entry creates the entry to be written, the same way logrus.NewEntry does. The number of frames the caller hook has to
+// skip is passed using the context of the entry.
 func (l logger) entry() *logrus.Entry {
+	entry := &logrus.Entry{Logger: l.logger, Data: make(logrus.Fields, 6), Context: l.ctx}
+	if len(l.fields) > 0 {
+		entry = entry.WithFields(l.fields)
+	}
 	if len(l.prefix) > 0 {
-		return l.logger.WithField("prefix", l.prefix)
+		entry = entry.WithField("prefix", l.prefix)
 	}
 
-	return logrus.NewEntry(l.logger)
+	return entry
 }
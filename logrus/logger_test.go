@@ -1,13 +1,21 @@
 package logrus_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"os"
 	"testing"
 
+	"github.com/corvus-ch/logr/buffered"
 	test "github.com/corvus-ch/logr/internal"
 	log "github.com/corvus-ch/logr/logrus"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Example() {
@@ -34,8 +42,141 @@ func Example() {
 	// level=error msg="Error level log message"
 	// level=error msg=4572726F72206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573
 	// level=info msg="This message has a prefix field" prefix=adipiscing
-	// level=debug msg="This message will be printed with debug level"
-	// level=debug msg=54686973206D6573736167652077696C6C206265207072696E7465642077697468206465627567206C6576656C206173206865782076616C756573
+	// level=debug msg="This message will be printed with debug level" v=1
+	// level=debug msg=54686973206D6573736167652077696C6C206265207072696E7465642077697468206465627567206C6576656C206173206865782076616C756573 v=1
+}
+
+func Example_withValues() {
+	tf := new(logrus.TextFormatter)
+	tf.DisableTimestamp = true
+	ll := &logrus.Logger{
+		Out:       os.Stdout,
+		Formatter: tf,
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.InfoLevel,
+	}
+	l := log.New(0, ll)
+	l.WithValues("user", "jane", "attempt", 3).Info("Login failed")
+	l.WithField("path", "/var/lib/dolor sit").Error("Not found")
+	// Output:
+	// level=info msg="Login failed" attempt=3 user=jane
+	// level=error msg="Not found" path="/var/lib/dolor sit"
+}
+
+func Example_nestedPrefix() {
+	tf := new(logrus.TextFormatter)
+	tf.DisableTimestamp = true
+	ll := &logrus.Logger{
+		Out:       os.Stdout,
+		Formatter: tf,
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.InfoLevel,
+	}
+	l := log.New(0, ll)
+	l.NewWithPrefix("db").NewWithPrefix("pool").Info("Connection established")
+	l.SetSeparator("/")
+	l.NewWithPrefix("http").NewWithPrefix("server").Info("Listening")
+	// Output:
+	// level=info msg="Connection established" prefix=db.pool
+	// level=info msg=Listening prefix=http/server
+}
+
+func Example_error() {
+	tf := new(logrus.TextFormatter)
+	tf.DisableTimestamp = true
+	ll := &logrus.Logger{
+		Out:       os.Stdout,
+		Formatter: tf,
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.InfoLevel,
+	}
+	l := log.New(0, ll)
+	err := fmt.Errorf("read config: %w", &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist})
+	l.Error("failed to start: ", err)
+	// Output:
+	// level=error msg="failed to start: read config: open /etc/app.conf: file does not exist" error="read config: open /etc/app.conf: file does not exist" errorCauses="[open /etc/app.conf: file does not exist file does not exist]"
+}
+
+func Example_levelMapper() {
+	tf := new(logrus.TextFormatter)
+	tf.DisableTimestamp = true
+	ll := &logrus.Logger{
+		Out:       os.Stdout,
+		Formatter: tf,
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.TraceLevel,
+	}
+	l := log.New(2, ll)
+	l.SetLevelMapper(log.TraceLevels)
+	l.V(1).Info("V(1) is written with debug level")
+	l.V(2).Info("V(2) is written with trace level")
+	// Output:
+	// level=debug msg="V(1) is written with debug level" v=1
+	// level=trace msg="V(2) is written with trace level" v=2
+}
+
+func Example_hook() {
+	l := buffered.New(1)
+	ll := logrus.New()
+	log.Redirect(ll, l)
+	ll.WithFields(logrus.Fields{"user": "jane", "attempt": 3}).Info("Info level log message")
+	ll.Warn("Warnings are written with warn level")
+	ll.WithError(errors.New("connection refused")).Error("Error level log message")
+	ll.Debug("Debug level log message")
+	ll.Trace("This message will not be printed as its verbosity exceeds the maximum")
+	log.New(0, ll).NewWithPrefix("db: ").Info("The prefix of the logr.Logger is restored")
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO Info level log message attempt=3 user=jane
+	// WARN Warnings are written with warn level
+	// ERROR Error level log message error="connection refused"
+	// V[1] Debug level log message
+	// INFO db: The prefix of the logr.Logger is restored
+}
+
+func TestNewKeepsHooks(t *testing.T) {
+	ll := logrus.New()
+	ll.SetOutput(ioutil.Discard)
+	log.New(0, ll).Info("Info level log message")
+	assert.Empty(t, ll.Hooks)
+}
+
+func TestToggleReportCaller(t *testing.T) {
+	ll := logrus.New()
+	ll.SetOutput(ioutil.Discard)
+	ll.AddHook(log.NewCallerHook())
+	l := log.New(0, ll)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			ll.SetReportCaller(i%2 == 0)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		l.Info("Info level log message")
+	}
+	<-done
+}
+
+func TestCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ll := logrus.New()
+	ll.SetOutput(buf)
+	ll.SetLevel(logrus.DebugLevel)
+	ll.SetFormatter(new(logrus.JSONFormatter))
+	ll.SetReportCaller(true)
+	ll.AddHook(log.NewCallerHook())
+	want := test.Callers(log.New(1, ll))
+
+	var got []string
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var entry struct{ File string }
+		require.NoError(t, dec.Decode(&entry))
+		got = append(got, entry.File)
+	}
+	test.AssertCallers(t, want, got)
 }
 
 func Benchmark(b *testing.B) {
@@ -0,0 +1,113 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/warn"
+)
+
+// NewHandler creates a slog.Handler writing to the given logr.Logger.
+//
+// Records with slog.LevelError or above are written using logr.Logger.Error. Records with slog.LevelWarn or above are
+// written using warn.Warn. Records with slog.LevelInfo or above are written using logr.Logger.Info. Lower levels are
+// written with logr.Logger.V, using the inverse of the mapping done by New: slog.LevelDebug becomes V(1),
+// slog.LevelDebug-1 becomes V(2) and so forth.
+//
+// Attributes are added using logr.Logger.WithField. Keys of attributes within a group are qualified with the group
+// name, separated by a dot.
+func NewHandler(l logr.Logger) slog.Handler {
+	return &handler{logger: l}
+}
+
+type handler struct {
+	logger logr.Logger
+	group  string
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	if level >= slog.LevelError {
+		return true
+	}
+
+	return h.logger.V(vLevel(level)).Enabled()
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	l := h.logger
+	r.Attrs(func(a slog.Attr) bool {
+		l = withAttr(l, h.group, a)
+		return true
+	})
+
+	switch {
+	case r.Level >= slog.LevelError:
+		l.Error(r.Message)
+	case r.Level >= slog.LevelWarn:
+		warn.Warn(l, r.Message)
+	default:
+		l.V(vLevel(r.Level)).Info(r.Message)
+	}
+
+	return nil
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	l := h.logger
+	for _, a := range attrs {
+		l = withAttr(l, h.group, a)
+	}
+
+	return &handler{logger: l, group: h.group}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &handler{logger: h.logger, group: qualify(h.group, name)}
+}
+
+func withAttr(l logr.Logger, group string, a slog.Attr) logr.Logger {
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		if a.Key == "" {
+			return l
+		}
+		return l.WithField(qualify(group, a.Key), v.Any())
+	}
+
+	if a.Key != "" {
+		group = qualify(group, a.Key)
+	}
+	for _, ga := range v.Group() {
+		l = withAttr(l, group, ga)
+	}
+
+	return l
+}
+
+func qualify(group, key string) string {
+	if group == "" {
+		return key
+	}
+
+	return group + "." + key
+}
+
+func vLevel(level slog.Level) int {
+	if level >= slog.LevelInfo {
+		return 0
+	}
+	if level >= slog.LevelDebug {
+		return 1
+	}
+
+	return int(slog.LevelDebug-level) + 1
+}
@@ -0,0 +1,193 @@
+// Package slog implements logr.Logger by Tim Hockin using log/slog from the standard library.
+//
+// It also provides a slog.Handler writing to a logr.Logger, allowing code using log/slog and code using logr.Logger
+// to share the same sink.
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/internal/errs"
+	"github.com/corvus-ch/logr/internal/kv"
+	"github.com/corvus-ch/logr/verbosity"
+)
+
+// New creates a new instance of logr.Logger.
+//
+// Info is written with slog.LevelInfo and Error with slog.LevelError. Sub loggers created using V(n) with n greater
+// than zero write with level slog.LevelDebug - (n - 1). This maps V(1) to slog.LevelDebug and any further verbosity
+// level to a level below.
+//
+// NewWithPrefix adds an attribute named "prefix". Calling NewWithPrefix on a logger which already has a prefix, joins
+// both prefixes using the separator and replaces the attribute. The separator defaults to "." and can be changed using
+// SetSeparator.
+//
+// If one of the arguments passed to Error or Errorf is an error, it is added as attribute named "error". The messages
+// of errors wrapped by it are added as attribute named "errorCauses". If enabled using SetStackTrace, the stack trace
+// of the call to Error or Errorf is added as attribute named "stack".
+func New(v int, l *slog.Logger) *logger {
+	return &logger{
+		level:     0,
+		verbosity: verbosity.New(v),
+		prefix:    "",
+		separator: ".",
+		base:      l,
+		logger:    l,
+	}
+}
+
+type logger struct {
+	logr.Logger
+	level     int
+	verbosity *verbosity.Level
+	prefix    string
+	separator string
+	stack     bool
+	// callerSkip is the number of additional stack frames skipped when determining the caller.
+	callerSkip int
+	// base is the slog.Logger without the prefix attribute. The attribute is added to logger each time the prefix
+	// changes.
+	base   *slog.Logger
+	logger *slog.Logger
+}
+
+// Info implements logr.Logger.Info() by writing a record with info level or a debug level in case a sub logger was
+// created using V() with level greater than zero.
+func (l logger) Info(args ...interface{}) {
+	if l.Enabled() {
+		l.log(l.slogLevel(), fmt.Sprint(args...))
+	}
+}
+
+// Infof implements logr.Logger.Infof() by writing a record with info level or a debug level in case a sub logger was
+// created using V() with level greater than zero.
+func (l logger) Infof(format string, args ...interface{}) {
+	if l.Enabled() {
+		l.log(l.slogLevel(), fmt.Sprintf(format, args...))
+	}
+}
+
+// Enabled implements logr.Logger.Enabled() by checking if the current verbosity level is less or equal than the loggers
+// maximum verbosity for the loggers prefix (see Verbosity) and if the slog.Handler is enabled for the matching level.
+func (l logger) Enabled() bool {
+	return l.verbosity.EnabledFor(l.prefix, l.level) && l.logger.Enabled(context.Background(), l.slogLevel())
+}
+
+// Error implements logr.Logger.Error() by writing a record with error level.
+func (l logger) Error(args ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprint(args...), l.errorAttrs(errs.Find(args))...)
+}
+
+// Errorf implements logr.Logger.Errorf() by writing a record with error level.
+func (l logger) Errorf(format string, args ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprintf(format, args...), l.errorAttrs(errs.Find(args))...)
+}
+
+// Warn implements warn.Logger.Warn by writing a record with warn level.
+func (l logger) Warn(args ...interface{}) {
+	l.log(slog.LevelWarn, fmt.Sprint(args...))
+}
+
+// Warnf implements warn.Logger.Warnf by writing a record with warn level.
+func (l logger) Warnf(format string, args ...interface{}) {
+	l.log(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// V implements logr.Logger.V.
+func (l logger) V(level int) logr.InfoLogger {
+	l.level = level
+	return l
+}
+
+// NewWithPrefix implements logr.Logger.NewWithPrefix by joining the prefix with the current one and setting it as
+// attribute named "prefix".
+func (l logger) NewWithPrefix(prefix string) logr.Logger {
+	if len(l.prefix) > 0 {
+		prefix = l.prefix + l.separator + prefix
+	}
+	l.prefix = prefix
+	l.logger = l.base.With(slog.String("prefix", prefix))
+	return l
+}
+
+// WithValues returns a logger which adds the given key/value pairs as attributes using slog.Logger.With.
+func (l logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	pairs := kv.Pairs(keysAndValues...)
+	attrs := make([]interface{}, len(pairs))
+	for i, p := range pairs {
+		attrs[i] = slog.Any(p.Key, p.Value)
+	}
+
+	l.base = l.base.With(attrs...)
+	l.logger = l.logger.With(attrs...)
+	return l
+}
+
+// WithField implements logr.Logger.WithField by calling WithValues with a single key/value pair.
+func (l logger) WithField(name string, value interface{}) logr.Logger {
+	return l.WithValues(name, value)
+}
+
+// WithCallerSkip returns a logger which skips additional stack frames when determining the caller. Wrappers around the
+// logger use it to have the caller of the wrapper reported instead of the wrapper itself.
+func (l logger) WithCallerSkip(skip int) logr.Logger {
+	l.callerSkip += skip
+	return l
+}
+
+// Verbosity returns the verbosity level shared by this logger and all loggers derived from it.
+func (l logger) Verbosity() *verbosity.Level {
+	return l.verbosity
+}
+
+// SetStackTrace enables or disables capturing the stack trace on calls to Error and Errorf.
+func (l *logger) SetStackTrace(enabled bool) {
+	l.stack = enabled
+}
+
+// SetSeparator sets the separator used to join prefixes of nested calls to NewWithPrefix.
+func (l *logger) SetSeparator(sep string) {
+	l.separator = sep
+}
+
+// log creates the record itself, so the source location points to the caller of the logr.Logger method and not to
+// this package.
+func (l logger) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3+l.callerSkip, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+func (l logger) errorAttrs(err error) []slog.Attr {
+	var attrs []slog.Attr
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		if causes := errs.Causes(err); len(causes) > 0 {
+			attrs = append(attrs, slog.Any("errorCauses", causes))
+		}
+	}
+	if l.stack {
+		attrs = append(attrs, slog.String("stack", errs.Stack(2+l.callerSkip)))
+	}
+
+	return attrs
+}
+
+func (l logger) slogLevel() slog.Level {
+	if l.level > 0 {
+		return slog.LevelDebug - slog.Level(l.level-1)
+	}
+
+	return slog.LevelInfo
+}
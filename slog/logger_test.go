@@ -0,0 +1,117 @@
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/corvus-ch/logr/buffered"
+	test "github.com/corvus-ch/logr/internal"
+	log "github.com/corvus-ch/logr/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func newSlog(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: slog.LevelDebug - 10,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func Example() {
+	l := log.New(2, newSlog(os.Stdout))
+	l.Info("Info level log message")
+	l.Infof("%X", "Info level log message printed in hex values")
+	l.Error("Error level log message")
+	l.Errorf("%X", "Error level log message printed in hex values")
+	l.NewWithPrefix("adipiscing").Info("This message has a prefix attribute")
+	l.WithValues("user", "jane").Info("This message has an attribute")
+	l.V(1).Info("This message will be printed with debug level")
+	l.V(2).Infof("%X", "This message will be printed below debug level as hex values")
+	l.V(3).Info("This message will not be printed as its verbosity exceeds the maximum")
+	// Output:
+	// level=INFO msg="Info level log message"
+	// level=INFO msg=496E666F206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573
+	// level=ERROR msg="Error level log message"
+	// level=ERROR msg=4572726F72206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573
+	// level=INFO msg="This message has a prefix attribute" prefix=adipiscing
+	// level=INFO msg="This message has an attribute" user=jane
+	// level=DEBUG msg="This message will be printed with debug level"
+	// level=DEBUG-1 msg=54686973206D6573736167652077696C6C206265207072696E7465642062656C6F77206465627567206C6576656C206173206865782076616C756573
+}
+
+func Example_error() {
+	l := log.New(0, newSlog(os.Stdout))
+	err := fmt.Errorf("read config: %w", &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist})
+	l.Error("failed to start: ", err)
+	// Output:
+	// level=ERROR msg="failed to start: read config: open /etc/app.conf: file does not exist" error="read config: open /etc/app.conf: file does not exist" errorCauses="[open /etc/app.conf: file does not exist file does not exist]"
+}
+
+func Example_handler() {
+	bl := buffered.New(1)
+	l := slog.New(log.NewHandler(bl))
+	l.Info("Info level log message", "user", "jane")
+	l.Warn("Warn level log message")
+	l.Error("Error level log message", slog.Group("req", "method", "GET"))
+	l.WithGroup("db").With("table", "users").Info("This message has qualified attributes")
+	l.Debug("This message will be printed with verbose level")
+	l.Log(context.Background(), slog.LevelDebug-1, "This message will not be printed as its verbosity exceeds the maximum")
+	bl.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO Info level log message user=jane
+	// WARN Warn level log message
+	// ERROR Error level log message req.method=GET
+	// INFO This message has qualified attributes db.table=users
+	// V[1] This message will be printed with verbose level
+}
+
+func Example_nestedPrefix() {
+	l := log.New(0, newSlog(os.Stdout))
+	l.NewWithPrefix("db").NewWithPrefix("pool").Info("Connection established")
+	l.SetSeparator("/")
+	l.NewWithPrefix("http").WithField("port", 80).NewWithPrefix("server").Info("Listening")
+	// Output:
+	// level=INFO msg="Connection established" prefix=db.pool
+	// level=INFO msg=Listening port=80 prefix=http/server
+}
+
+func TestCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	want := test.Callers(log.New(1, slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		AddSource: true,
+		Level:     slog.LevelDebug,
+	}))))
+
+	var got []string
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var record struct{ Source slog.Source }
+		require.NoError(t, dec.Decode(&record))
+		got = append(got, fmt.Sprintf("%s:%d", record.Source.File, record.Source.Line))
+	}
+	test.AssertCallers(t, want, got)
+}
+
+func Benchmark(b *testing.B) {
+	l := log.New(1, newSlog(io.Discard))
+	test.Benchmark(b, "error", l.Error)
+	test.Benchmarkf(b, "errorf", l.Errorf)
+	test.Benchmark(b, "info", l.Info)
+	test.Benchmarkf(b, "infof", l.Infof)
+	test.Benchmark(b, "v", l.V(1).Info)
+	test.Benchmarkf(b, "vf", l.V(1).Infof)
+	test.Benchmark(b, "disabled", l.V(2).Info)
+	test.Benchmarkf(b, "disabledf", l.V(2).Infof)
+}
@@ -6,6 +6,9 @@ import (
 	"log"
 
 	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/internal/errs"
+	"github.com/corvus-ch/logr/internal/kv"
+	"github.com/corvus-ch/logr/verbosity"
 )
 
 // New creates a new instance of logr.Logger.
@@ -13,16 +16,29 @@ import (
 // The function takes one or several *log.Logger instances. If only one *log.Logger is provided, this logger will be
 // used for all log levels. If two *log.Logger instances are provided, the first one will be used for logs with level
 // error and the second for all info levels. If tree or more *log.Logger instances are provided, the third logger and
-// any consecutive loggers are used for the verbose levels created with logr.Logger.V().
+// any consecutive loggers are used for the verbose levels created with logr.Logger.V(). Warnings are written to the
+// logger used for info level, unless a dedicated one is set using SetWarnLogger.
 //
-// The verbosity defines the upper limit. When creating a new sub logger using logr.Logger.V(), if the level passed to
-// V is greater than verbosity, the sub logger will be silenced.
+// The verbosity v defines the upper limit. When creating a new sub logger using logr.Logger.V(), if the level passed
+// to V is greater than the verbosity, the sub logger will be silenced. The verbosity is shared by all loggers derived
+// from the returned logger and can be changed at runtime using Verbosity.
 //
-// If verbosity is smaller than the number of *log.Logger instances, any additional logger will be ignored. If verbosity
-// is greater than the number of *log.Logger instances, the last logger will be used to fill in for any missing levels.
+// If verbosity is smaller than the number of *log.Logger instances, any additional logger will be ignored until the
+// verbosity is raised. If verbosity is greater than the number of *log.Logger instances, the last logger will be used
+// to fill in for any missing levels.
 //
 // This implementation takes control over the prefix of *log.Logger. Any prefix set on instantiation, will be ignored.
-// Flags are preserved.
+// Flags are preserved. Calling NewWithPrefix on a logger which already has a prefix, joins both prefixes using the
+// separator. The separator defaults to "." and can be changed using SetSeparator. The prefix is written as is, unless a
+// separator between the prefix and the message is set using SetPrefixTerminator. The prefix is rendered for each call
+// individually and the *log.Logger instances are never modified. It is therefore safe to use loggers with different
+// prefixes concurrently, even if they share the same *log.Logger instances.
+//
+// Key/value pairs added using WithValues or WithField are appended to the message as space separated list of key=value.
+//
+// If one of the arguments passed to Error or Errorf is an error wrapping other errors, the messages of the wrapped
+// errors are written on separate, indented lines below the message. If enabled using SetStackTrace, the stack trace of
+// the call to Error or Errorf is written below as well.
 //
 // Example:
 //
@@ -51,19 +67,16 @@ import (
 //     lgr3.V(1).Info("I will be ignored")
 //     lgr3.V(2).Info("And I will be ignored too")
 //
-func New(verbosity int, ll ...*log.Logger) *Logger {
-	loggers := make([]*log.Logger, verbosity+2)
-	for i := range loggers {
-		if i < len(ll) {
-			loggers[i] = ll[i]
-		} else {
-			loggers[i] = ll[len(ll)-1]
-		}
+func New(v int, ll ...*log.Logger) *Logger {
+	loggers := append([]*log.Logger{}, ll...)
+	if len(loggers) == 1 {
+		loggers = append(loggers, ll[0])
 	}
 	return &Logger{
 		level:     0,
-		verbosity: verbosity,
+		verbosity: verbosity.New(v),
 		prefix:    "",
+		separator: ".",
 		loggers:   loggers,
 		callDepth: 2,
 	}
@@ -72,66 +85,129 @@ func New(verbosity int, ll ...*log.Logger) *Logger {
 type Logger struct {
 	logr.Logger
 	level     int
-	verbosity int
+	verbosity *verbosity.Level
 	prefix    string
+	separator string
+	values    []kv.Pair
 	loggers   []*log.Logger
+	warn      *log.Logger
 	callDepth int
+	stack     bool
+	// terminator is written after a non empty prefix.
+	terminator string
 }
 
 // Info implements logr.Logger.Info by writing to log.Logger of with the matching level.
 func (l Logger) Info(args ...interface{}) {
 	if l.Enabled() {
-		l.loggers[l.index()].SetPrefix(l.prefix)
-		l.loggers[l.index()].Output(l.callDepth, fmt.Sprint(args...))
+		l.output(l.callDepth, l.loggers[l.index()], l.message(fmt.Sprint(args...)))
 	}
 }
 
 // Infof implements logr.Logger.Infof by writing to log.Logger of with the matching level.
 func (l Logger) Infof(format string, args ...interface{}) {
 	if l.Enabled() {
-		l.loggers[l.index()].SetPrefix(l.prefix)
-		l.loggers[l.index()].Output(l.callDepth, fmt.Sprintf(format, args...))
+		l.output(l.callDepth, l.loggers[l.index()], l.message(fmt.Sprintf(format, args...)))
 	}
 }
 
 // Enabled implements logr.Logger.Enabled by checking if the current verbosity level is less or equal than the loggers
-// maximum verbosity.
+// maximum verbosity for the loggers prefix. The maximum verbosity can be changed at any time using Verbosity, including
+// per prefix overrides.
 func (l Logger) Enabled() bool {
-	return l.level <= l.verbosity
+	return l.verbosity.EnabledFor(l.prefix, l.level)
 }
 
 // Error implements logr.Logger.Error by writing to the first log.Logger.
 func (l Logger) Error(args ...interface{}) {
-	l.loggers[0].SetPrefix(l.prefix)
-	l.loggers[0].Output(l.callDepth, fmt.Sprint(args...))
+	l.error(fmt.Sprint(args...), errs.Find(args))
 }
 
 // Errorf implements logr.Logger.Errorf by writing to the first log.Logger.
 func (l Logger) Errorf(format string, args ...interface{}) {
-	l.loggers[0].SetPrefix(l.prefix)
-	l.loggers[0].Output(l.callDepth, fmt.Sprintf(format, args...))
+	l.error(fmt.Sprintf(format, args...), errs.Find(args))
+}
+
+// Warn implements warn.Logger.Warn by writing to the *log.Logger set using SetWarnLogger.
+func (l Logger) Warn(args ...interface{}) {
+	l.output(l.callDepth, l.warnLogger(), l.message(fmt.Sprint(args...)))
+}
+
+// Warnf implements warn.Logger.Warnf by writing to the *log.Logger set using SetWarnLogger.
+func (l Logger) Warnf(format string, args ...interface{}) {
+	l.output(l.callDepth, l.warnLogger(), l.message(fmt.Sprintf(format, args...)))
 }
 
 // V implements logr.Logger.V.
 func (l Logger) V(level int) logr.InfoLogger {
-	return Logger{
-		level:     level,
-		verbosity: l.verbosity,
-		prefix:    l.prefix,
-		loggers:   l.loggers,
-		callDepth: l.callDepth,
-	}
+	l.level = level
+	return l
 }
 
-// NewWithPrefix implements logr.Logger.NewWithPrefix.
+// NewWithPrefix implements logr.Logger.NewWithPrefix by joining the prefix with the current one.
 func (l Logger) NewWithPrefix(prefix string) logr.Logger {
-	return Logger{
-		level:     l.level,
-		verbosity: l.verbosity,
-		prefix:    prefix,
-		loggers:   l.loggers,
-		callDepth: l.callDepth,
+	if len(l.prefix) > 0 {
+		prefix = l.prefix + l.separator + prefix
+	}
+	l.prefix = prefix
+	return l
+}
+
+// WithValues returns a logger which appends the given key/value pairs to each message.
+func (l Logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	l.values = kv.Append(l.values, kv.Pairs(keysAndValues...)...)
+	return l
+}
+
+// WithField implements logr.Logger.WithField by calling WithValues with a single key/value pair.
+func (l Logger) WithField(name string, value interface{}) logr.Logger {
+	return l.WithValues(name, value)
+}
+
+// WithCallerSkip returns a logger which skips additional stack frames when determining the caller. Wrappers around the
+// logger use it to have the caller of the wrapper reported instead of the wrapper itself. It is added to the call depth
+// passed to log.Logger.Output.
+func (l Logger) WithCallerSkip(skip int) logr.Logger {
+	l.callDepth += skip
+	return l
+}
+
+// Verbosity returns the verbosity level shared by this logger and all loggers derived from it.
+func (l Logger) Verbosity() *verbosity.Level {
+	return l.verbosity
+}
+
+// Loggers returns the *log.Logger instances used by the logger. Changing their flags or output affects all loggers
+// derived from this logger.
+func (l Logger) Loggers() []*log.Logger {
+	loggers := append([]*log.Logger{}, l.loggers...)
+	if l.warn != nil {
+		loggers = append(loggers, l.warn)
 	}
+
+	return loggers
+}
+
+// SetWarnLogger sets the *log.Logger used by Warn and Warnf. Unless set, warnings are written to the *log.Logger used
+// for info level.
+func (l *Logger) SetWarnLogger(lgr *log.Logger) {
+	l.warn = lgr
+}
+
+// SetSeparator sets the separator used to join prefixes of nested calls to NewWithPrefix.
+func (l *Logger) SetSeparator(sep string) {
+	l.separator = sep
+}
+
+// SetPrefixTerminator sets the string written after the prefix, for example ": " to separate the prefix from the
+// message. It is not written for loggers without a prefix. The default is an empty string.
+func (l *Logger) SetPrefixTerminator(term string) {
+	l.terminator = term
+}
+
+// SetStackTrace enables or disables capturing the stack trace on calls to Error and Errorf.
+func (l *Logger) SetStackTrace(enabled bool) {
+	l.stack = enabled
 }
 
 // SetCallDepth sets the call depth passed to log.Logger.Output.
@@ -139,6 +215,34 @@ func (l *Logger) SetCallDepth(depth int) {
 	l.callDepth = depth
 }
 
+func (l Logger) error(msg string, err error) {
+	var stack string
+	if l.stack {
+		stack = errs.Stack(l.callDepth)
+	}
+	l.output(l.callDepth+1, l.loggers[0], l.message(msg)+errs.Details(err, stack))
+}
+
+func (l Logger) warnLogger() *log.Logger {
+	if l.warn != nil {
+		return l.warn
+	}
+
+	return l.loggers[1]
+}
+
 func (l Logger) index() int {
-	return l.level + 1
+	if i := l.level + 1; i < len(l.loggers) {
+		return i
+	}
+
+	return len(l.loggers) - 1
+}
+
+func (l Logger) message(msg string) string {
+	if len(l.values) == 0 {
+		return msg
+	}
+
+	return msg + " " + kv.Format(l.values)
 }
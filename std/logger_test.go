@@ -3,13 +3,17 @@ package std_test
 import (
 	"bytes"
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	stdlog "log"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	test "github.com/corvus-ch/logr/internal"
 	log "github.com/corvus-ch/logr/std"
+	"github.com/stretchr/testify/assert"
 )
 
 func Example() {
@@ -23,13 +27,13 @@ func Example() {
 	l.V(1).Infof("%X", "Debug level message in hex values")
 	l.V(2).Info("This message will not be printed as its verbosity exceeds the maximum")
 	// Output:
-	// logger_test.go:17: Info level log message
-	// logger_test.go:18: 496E666F206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573
-	// logger_test.go:19: Error level log message
-	// logger_test.go:20: 4572726F72206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573
-	// adipiscinglogger_test.go:21: This message is prefixed
-	// logger_test.go:22: Debug level message
-	// logger_test.go:23: 4465627567206C6576656C206D65737361676520696E206865782076616C756573
+	// logger_test.go:21: Info level log message
+	// logger_test.go:22: 496E666F206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573
+	// logger_test.go:23: Error level log message
+	// logger_test.go:24: 4572726F72206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573
+	// adipiscinglogger_test.go:25: This message is prefixed
+	// logger_test.go:26: Debug level message
+	// logger_test.go:27: 4465627567206C6576656C206D65737361676520696E206865782076616C756573
 }
 
 func Example_twoLoggers() {
@@ -97,6 +101,135 @@ func Example_threeLoggers() {
 	// 54686973206D6573736167652077696C6C206265207072696E7465642077697468206465627567206C6576656C206173206865782076616C756573
 }
 
+func Example_withValues() {
+	l := log.New(0, stdlog.New(os.Stdout, "", 0))
+	l.WithValues("user", "jane", "attempt", 3).Info("Login failed")
+	l.WithField("path", "/var/lib/dolor sit").Error("Not found")
+	// Output:
+	// Login failed user=jane attempt=3
+	// Not found path="/var/lib/dolor sit"
+}
+
+func Example_nestedPrefix() {
+	l := log.New(0, stdlog.New(os.Stdout, "", 0))
+	l.NewWithPrefix("db").NewWithPrefix("pool: ").Info("Connection established")
+	l.SetSeparator("/")
+	l.NewWithPrefix("http").NewWithPrefix("server: ").Info("Listening")
+	// Output:
+	// db.pool: Connection established
+	// http/server: Listening
+}
+
+func Example_msgPrefix() {
+	l := log.New(0, stdlog.New(os.Stdout, "ignored", stdlog.Lshortfile|stdlog.Lmsgprefix))
+	l.NewWithPrefix("adipiscing: ").Info("The prefix is written after the header")
+	// Output:
+	// logger_test.go:125: adipiscing: The prefix is written after the header
+}
+
+func Example_prefixTerminator() {
+	l := log.New(0, stdlog.New(os.Stdout, "", stdlog.Lmsgprefix))
+	l.SetPrefixTerminator(": ")
+	l.Info("Messages without prefix are not terminated")
+	l.NewWithPrefix("db").NewWithPrefix("pool").Info("The prefix is separated from the message")
+	// Output:
+	// Messages without prefix are not terminated
+	// db.pool: The prefix is separated from the message
+}
+
+func Example_error() {
+	l := log.New(0, stdlog.New(os.Stdout, "", 0))
+	err := fmt.Errorf("read config: %w", &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist})
+	l.WithValues("attempt", 3).Errorf("failed to start: %v", err)
+	// Output:
+	// failed to start: read config: open /etc/app.conf: file does not exist attempt=3
+	//     caused by: open /etc/app.conf: file does not exist
+	//     caused by: file does not exist
+}
+
+func TestConcurrentPrefix(t *testing.T) {
+	const n = 500
+	buf := &bytes.Buffer{}
+	l := log.New(0, stdlog.New(buf, "", 0))
+	prefixes := []string{"", "lorem ", "ipsum ", "dolor "}
+	var wg sync.WaitGroup
+	for _, prefix := range prefixes {
+		wg.Add(1)
+		go func(prefix string) {
+			defer wg.Done()
+			pl := l.NewWithPrefix(prefix)
+			for i := 0; i < n; i++ {
+				pl.Info(prefix)
+			}
+		}(prefix)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, n*len(prefixes))
+	for _, line := range lines {
+		half := len(line) / 2
+		assert.Equal(t, line[:half], line[half:], "prefix does not match the message")
+	}
+}
+
+func TestConcurrentLoggers(t *testing.T) {
+	const n = 500
+	buf := &bytes.Buffer{}
+	sl := stdlog.New(buf, "", 0)
+	loggers := []*log.Logger{log.New(0, sl), log.New(0, sl)}
+	var wg sync.WaitGroup
+	for i, l := range loggers {
+		wg.Add(1)
+		go func(msg string, l *log.Logger) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				l.Info(msg)
+			}
+		}(fmt.Sprintf("logger %d", i), l)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, n*len(loggers))
+	for _, line := range lines {
+		assert.Regexp(t, `^logger \d$`, line)
+	}
+}
+
+func Example_verbosity() {
+	buf := &bytes.Buffer{}
+	l := log.New(0, stdlog.New(os.Stdout, "", 0), stdlog.New(os.Stdout, "", 0), stdlog.New(buf, "", 0))
+	l.V(1).Info("This message will not be printed as its verbosity exceeds the maximum")
+	l.Verbosity().Set(2)
+	l.V(1).Info("This message is written to the third logger")
+	l.V(2).Info("And so is this one")
+	buf.WriteTo(os.Stdout)
+	// Output:
+	// This message is written to the third logger
+	// And so is this one
+}
+
+func TestCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	want := test.Callers(log.New(1, stdlog.New(buf, "", stdlog.Llongfile|stdlog.Lmsgprefix)))
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		got = append(got, strings.SplitN(line, ": ", 2)[0])
+	}
+	test.AssertCallers(t, want, got)
+}
+
+func Example_warn() {
+	l := log.New(0, stdlog.New(ioutil.Discard, "", 0))
+	l.Warn("Written to the logger used for info level, which discards it")
+	l.SetWarnLogger(stdlog.New(os.Stdout, "", 0))
+	l.Warnf("Written to the %s logger", "dedicated")
+	// Output:
+	// Written to the dedicated logger
+}
+
 func Benchmark(b *testing.B) {
 	l := log.New(
 		1,
@@ -0,0 +1,128 @@
+package std
+
+import (
+	"log"
+	"runtime"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// locks serialise the writes of this package. As output bypasses log.Logger.Output, the mutex of the *log.Logger is
+// not held. Instead, each *log.Logger is assigned one of a fixed number of mutexes based on its address, so all loggers
+// created by New writing to the same *log.Logger serialise on the same mutex. Unrelated *log.Logger instances might
+// share a mutex, but no memory is retained for *log.Logger instances no longer used.
+var locks [64]sync.Mutex
+
+// lock returns the mutex serialising the writes of this package to the given *log.Logger.
+func lock(lgr *log.Logger) *sync.Mutex {
+	return &locks[(uintptr(unsafe.Pointer(lgr))>>4)%uintptr(len(locks))]
+}
+
+// output writes a single log line to the writer of the given *log.Logger.
+//
+// Unlike log.Logger.Output, the prefix is passed in instead of being read from the logger. This allows to render the
+// prefix of each logr.Logger individually without calling log.Logger.SetPrefix on the shared instance. The flags of
+// the *log.Logger are honored the same way log.Logger.Output does.
+//
+// The calldepth has the same meaning as for log.Logger.Output.
+//
+// Writes are serialised with all other loggers of this package writing to the same *log.Logger. Calling the methods of
+// the *log.Logger directly at the same time is only safe if its writer is safe for concurrent use.
+func (l Logger) output(calldepth int, lgr *log.Logger, msg string) {
+	now := time.Now()
+	flag := lgr.Flags()
+	file, line := "???", 0
+	if flag&(log.Lshortfile|log.Llongfile) != 0 {
+		var ok bool
+		if _, file, line, ok = runtime.Caller(calldepth); !ok {
+			file, line = "???", 0
+		}
+	}
+
+	prefix := l.prefix
+	if len(prefix) > 0 {
+		prefix += l.terminator
+	}
+
+	buf := make([]byte, 0, len(prefix)+len(msg)+64)
+	buf = formatHeader(buf, now, prefix, flag, file, line)
+	buf = append(buf, msg...)
+	if len(msg) == 0 || msg[len(msg)-1] != '\n' {
+		buf = append(buf, '\n')
+	}
+
+	mu := lock(lgr)
+	mu.Lock()
+	defer mu.Unlock()
+	lgr.Writer().Write(buf)
+}
+
+// formatHeader mimics the header written by log.Logger.
+func formatHeader(buf []byte, t time.Time, prefix string, flag int, file string, line int) []byte {
+	if flag&log.Lmsgprefix == 0 {
+		buf = append(buf, prefix...)
+	}
+	if flag&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		if flag&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		if flag&log.Ldate != 0 {
+			year, month, day := t.Date()
+			buf = itoa(buf, year, 4)
+			buf = append(buf, '/')
+			buf = itoa(buf, int(month), 2)
+			buf = append(buf, '/')
+			buf = itoa(buf, day, 2)
+			buf = append(buf, ' ')
+		}
+		if flag&(log.Ltime|log.Lmicroseconds) != 0 {
+			hour, min, sec := t.Clock()
+			buf = itoa(buf, hour, 2)
+			buf = append(buf, ':')
+			buf = itoa(buf, min, 2)
+			buf = append(buf, ':')
+			buf = itoa(buf, sec, 2)
+			if flag&log.Lmicroseconds != 0 {
+				buf = append(buf, '.')
+				buf = itoa(buf, t.Nanosecond()/1e3, 6)
+			}
+			buf = append(buf, ' ')
+		}
+	}
+	if flag&(log.Lshortfile|log.Llongfile) != 0 {
+		if flag&log.Lshortfile != 0 {
+			for i := len(file) - 1; i > 0; i-- {
+				if file[i] == '/' {
+					file = file[i+1:]
+					break
+				}
+			}
+		}
+		buf = append(buf, file...)
+		buf = append(buf, ':')
+		buf = itoa(buf, line, -1)
+		buf = append(buf, ": "...)
+	}
+	if flag&log.Lmsgprefix != 0 {
+		buf = append(buf, prefix...)
+	}
+
+	return buf
+}
+
+// itoa appends the decimal representation of i to buf, zero padded to wid digits.
+func itoa(buf []byte, i int, wid int) []byte {
+	var b [20]byte
+	bp := len(b) - 1
+	for i >= 10 || wid > 1 {
+		wid--
+		q := i / 10
+		b[bp] = byte('0' + i - q*10)
+		bp--
+		i = q
+	}
+	b[bp] = byte('0' + i)
+
+	return append(buf, b[bp:]...)
+}
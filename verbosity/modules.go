@@ -0,0 +1,87 @@
+package verbosity
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Module overrides the verbosity for loggers whose prefix matches the pattern.
+//
+// The pattern is either the exact prefix or a glob as understood by path.Match, for example "db.*".
+type Module struct {
+	Pattern   string
+	Verbosity int
+}
+
+// Modules is a list of per prefix verbosity overrides, similar to the -vmodule flag of glog.
+//
+// Modules implements flag.Value, so it can be used directly as command line flag.
+type Modules []Module
+
+// ParseModules parses a comma separated list of pattern=verbosity, for example "db.*=4,http=2".
+func ParseModules(s string) (Modules, error) {
+	var m Modules
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pattern, value, ok := strings.Cut(entry, "=")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("invalid module %q: expected pattern=verbosity", entry)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid module %q: %w", entry, err)
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid module %q: verbosity must be an integer", entry)
+		}
+		m = append(m, Module{pattern, v})
+	}
+
+	return m, nil
+}
+
+// Lookup returns the verbosity for the given prefix.
+//
+// A module with a pattern equal to the prefix takes precedence. Otherwise the first module with a matching glob
+// pattern is used. The second return value is false if no module matches.
+func (m Modules) Lookup(prefix string) (int, bool) {
+	for _, mod := range m {
+		if mod.Pattern == prefix {
+			return mod.Verbosity, true
+		}
+	}
+	for _, mod := range m {
+		if ok, _ := path.Match(mod.Pattern, prefix); ok {
+			return mod.Verbosity, true
+		}
+	}
+
+	return 0, false
+}
+
+// String implements flag.Value and fmt.Stringer by rendering the modules in the format accepted by ParseModules.
+func (m Modules) String() string {
+	entries := make([]string, len(m))
+	for i, mod := range m {
+		entries[i] = mod.Pattern + "=" + strconv.Itoa(mod.Verbosity)
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// Set implements flag.Value by parsing the value using ParseModules.
+func (m *Modules) Set(s string) error {
+	parsed, err := ParseModules(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Package verbosity provides a verbosity level which can be changed while the application is running.
+//
+// All loggers derived from one call to New of the adapters in this library share the same *Level. Changing it, changes
+// which V levels are enabled for all of them.
+//
+// Besides the global verbosity, a Level can hold per prefix overrides in the style of the -vmodule flag of glog. This
+// allows to raise the verbosity of single sub loggers created with NewWithPrefix:
+//
+//	m, err := verbosity.ParseModules("db.*=4,http=2")
+//	if err != nil {
+//		panic(err)
+//	}
+//	l := zap.New(1, z)
+//	l.Verbosity().SetModules(m)
+//	l.NewWithPrefix("db").NewWithPrefix("pool").V(4).Info("I will be written")
+package verbosity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// Level holds the maximum verbosity level and the per prefix overrides. It is safe for concurrent use.
+type Level struct {
+	v       atomic.Int32
+	modules atomic.Pointer[Modules]
+}
+
+// New creates a new Level with the given verbosity.
+func New(v int) *Level {
+	l := &Level{}
+	l.Set(v)
+	return l
+}
+
+// Get returns the current verbosity.
+func (l *Level) Get() int {
+	return int(l.v.Load())
+}
+
+// Set changes the verbosity.
+func (l *Level) Set(v int) {
+	l.v.Store(int32(v))
+}
+
+// Enabled checks if the given V level is less or equal than the current verbosity.
+func (l *Level) Enabled(level int) bool {
+	return level <= l.Get()
+}
+
+// Modules returns the per prefix overrides.
+func (l *Level) Modules() Modules {
+	if m := l.modules.Load(); m != nil {
+		return *m
+	}
+
+	return nil
+}
+
+// SetModules replaces the per prefix overrides.
+func (l *Level) SetModules(m Modules) {
+	l.modules.Store(&m)
+}
+
+// For returns the verbosity for the given prefix. If none of the modules matches the prefix, the verbosity set using
+// Set is returned.
+func (l *Level) For(prefix string) int {
+	if v, ok := l.Modules().Lookup(prefix); ok {
+		return v
+	}
+
+	return l.Get()
+}
+
+// EnabledFor checks if the given V level is less or equal than the verbosity for the given prefix.
+func (l *Level) EnabledFor(prefix string, level int) bool {
+	return level <= l.For(prefix)
+}
+
+type payload struct {
+	Verbosity *int `json:"verbosity"`
+}
+
+type errorPayload struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP implements http.Handler, allowing to inspect and change the verbosity over HTTP.
+//
+// A GET request returns the current verbosity as JSON:
+//
+//	{"verbosity":1}
+//
+// A PUT request with a body of the same format changes the verbosity and returns the new value.
+func (l *Level) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req payload
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			enc.Encode(errorPayload{fmt.Sprintf("request body must be valid JSON: %v", err)})
+			return
+		}
+		if req.Verbosity == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			enc.Encode(errorPayload{"request body must contain the field verbosity"})
+			return
+		}
+		l.Set(*req.Verbosity)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		enc.Encode(errorPayload{"only GET and PUT are supported"})
+		return
+	}
+
+	v := l.Get()
+	enc.Encode(payload{&v})
+}
@@ -0,0 +1,176 @@
+package verbosity_test
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	stdlog "log"
+	stdslog "log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/buffered"
+	"github.com/corvus-ch/logr/logrus"
+	"github.com/corvus-ch/logr/slog"
+	"github.com/corvus-ch/logr/std"
+	"github.com/corvus-ch/logr/verbosity"
+	"github.com/corvus-ch/logr/zap"
+	"github.com/corvus-ch/logr/zerolog"
+	rszerolog "github.com/rs/zerolog"
+	sirupsen "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	uberzap "go.uber.org/zap"
+)
+
+func Example() {
+	l := buffered.New(0)
+	db := l.NewWithPrefix("db: ")
+	db.V(1).Info("This message will not be printed as its verbosity exceeds the maximum")
+	l.Verbosity().Set(1)
+	db.V(1).Info("This message is printed after raising the verbosity")
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// V[1] db: This message is printed after raising the verbosity
+}
+
+func ExampleLevel_ServeHTTP() {
+	l := buffered.New(0)
+	srv := httptest.NewServer(l.Verbosity())
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader(`{"verbosity":2}`))
+	res, _ := http.DefaultClient.Do(req)
+	res.Body.Close()
+
+	l.V(2).Info("This message is printed after raising the verbosity over HTTP")
+	l.Buf().WriteTo(os.Stdout)
+	fmt.Println(l.Verbosity().Get())
+	// Output:
+	// V[2] This message is printed after raising the verbosity over HTTP
+	// 2
+}
+
+func TestLevel_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		code   int
+		resp   string
+		level  int
+	}{
+		{"get", http.MethodGet, "", http.StatusOK, `{"verbosity":1}`, 1},
+		{"put", http.MethodPut, `{"verbosity":3}`, http.StatusOK, `{"verbosity":3}`, 3},
+		{"invalid json", http.MethodPut, `{`, http.StatusBadRequest, `{"error":"request body must be valid JSON: unexpected EOF"}`, 1},
+		{"missing field", http.MethodPut, `{}`, http.StatusBadRequest, `{"error":"request body must contain the field verbosity"}`, 1},
+		{"unsupported method", http.MethodPost, "", http.StatusMethodNotAllowed, `{"error":"only GET and PUT are supported"}`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := verbosity.New(1)
+			rec := httptest.NewRecorder()
+			l.ServeHTTP(rec, httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.code, rec.Code)
+			assert.JSONEq(t, tt.resp, rec.Body.String())
+			assert.Equal(t, tt.level, l.Get())
+		})
+	}
+}
+
+func ExampleModules() {
+	var m verbosity.Modules
+	fs := flag.NewFlagSet("example", flag.ContinueOnError)
+	fs.Var(&m, "vmodule", "comma separated list of pattern=verbosity")
+	fs.Parse([]string{"-vmodule", "db.*=4,http=2"})
+
+	l := buffered.New(1)
+	l.Verbosity().SetModules(m)
+	l.NewWithPrefix("db").NewWithPrefix("pool").V(4).Info(": Verbosity of db.* is 4")
+	l.NewWithPrefix("http").V(2).Info(": Verbosity of http is 2")
+	l.NewWithPrefix("http").V(3).Info(": This message will not be printed as its verbosity exceeds the override")
+	l.V(2).Info("This message will not be printed as its verbosity exceeds the maximum")
+	l.Buf().WriteTo(os.Stdout)
+	fmt.Println(m)
+	// Output:
+	// V[4] db.pool: Verbosity of db.* is 4
+	// V[2] http: Verbosity of http is 2
+	// db.*=4,http=2
+}
+
+func TestParseModules(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		m    verbosity.Modules
+		err  string
+	}{
+		{"empty", "", nil, ""},
+		{"single", "db=4", verbosity.Modules{{"db", 4}}, ""},
+		{"multiple", " db.* = 4, ,http=2", verbosity.Modules{{"db.*", 4}, {"http", 2}}, ""},
+		{"missing verbosity", "db", nil, `invalid module "db": expected pattern=verbosity`},
+		{"missing pattern", "=1", nil, `invalid module "=1": expected pattern=verbosity`},
+		{"invalid verbosity", "db=high", nil, `invalid module "db=high": verbosity must be an integer`},
+		{"invalid pattern", "db[=1", nil, `invalid module "db[=1": syntax error in pattern`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := verbosity.ParseModules(tt.in)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.m, m)
+		})
+	}
+}
+
+func TestModules_Lookup(t *testing.T) {
+	m := verbosity.Modules{{"db.*", 4}, {"db.pool", 2}, {"*", 1}}
+	tests := []struct {
+		prefix string
+		v      int
+		ok     bool
+	}{
+		{"db.pool", 2, true},
+		{"db.conn", 4, true},
+		{"http", 1, true},
+		{"", 1, true},
+	}
+	for _, tt := range tests {
+		v, ok := m.Lookup(tt.prefix)
+		assert.Equal(t, tt.v, v, tt.prefix)
+		assert.Equal(t, tt.ok, ok, tt.prefix)
+	}
+	_, ok := verbosity.Modules{{"db", 4}}.Lookup("http")
+	assert.False(t, ok)
+}
+
+func TestEnabledFor(t *testing.T) {
+	loggers := map[string]interface {
+		logr.Logger
+		Verbosity() *verbosity.Level
+	}{
+		"buffered": buffered.New(1),
+		"logrus":   logrus.New(1, sirupsen.New()),
+		"slog":     slog.New(1, stdslog.New(stdslog.NewTextHandler(io.Discard, &stdslog.HandlerOptions{Level: stdslog.Level(-10)}))),
+		"std":      std.New(1, stdlog.New(io.Discard, "", 0)),
+		"zap":      zap.New(1, uberzap.NewNop()),
+		"zerolog":  zerolog.New(1, rszerolog.Nop()),
+	}
+	for name, l := range loggers {
+		t.Run(name, func(t *testing.T) {
+			l.Verbosity().SetModules(verbosity.Modules{{"db.*", 4}, {"http", 0}})
+			assert.True(t, l.V(1).Enabled())
+			assert.False(t, l.V(2).Enabled())
+			assert.True(t, l.NewWithPrefix("db").NewWithPrefix("pool").V(4).Enabled())
+			assert.False(t, l.NewWithPrefix("db").NewWithPrefix("pool").V(5).Enabled())
+			assert.False(t, l.NewWithPrefix("db").V(2).Enabled())
+			assert.False(t, l.NewWithPrefix("http").V(1).Enabled())
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Package warn provides an optional extension to logr.Logger by Tim Hockin, adding a warning level.
+//
+// All loggers of this project implement Logger. Use Warn and Warnf to write a warning to any logr.Logger, falling back
+// to Info for loggers not implementing it.
+package warn
+
+import (
+	"github.com/bketelsen/logr"
+)
+
+// Logger is implemented by loggers supporting a warning level.
+type Logger interface {
+	// Warn writes a message with warning level.
+	Warn(args ...interface{})
+	// Warnf writes a formatted message with warning level.
+	Warnf(format string, args ...interface{})
+}
+
+// Warn calls Warn of the logger if it implements Logger and Info otherwise.
+func Warn(l logr.Logger, args ...interface{}) {
+	l = withCallerSkip(l)
+	if wl, ok := l.(Logger); ok {
+		wl.Warn(args...)
+		return
+	}
+	l.Info(args...)
+}
+
+// Warnf calls Warnf of the logger if it implements Logger and Infof otherwise.
+func Warnf(l logr.Logger, format string, args ...interface{}) {
+	l = withCallerSkip(l)
+	if wl, ok := l.(Logger); ok {
+		wl.Warnf(format, args...)
+		return
+	}
+	l.Infof(format, args...)
+}
+
+// withCallerSkip skips the frame of Warn and Warnf, if supported by the logger.
+func withCallerSkip(l logr.Logger) logr.Logger {
+	if cs, ok := l.(interface{ WithCallerSkip(int) logr.Logger }); ok {
+		return cs.WithCallerSkip(1)
+	}
+
+	return l
+}
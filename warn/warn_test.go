@@ -0,0 +1,22 @@
+package warn_test
+
+import (
+	"os"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/buffered"
+	"github.com/corvus-ch/logr/warn"
+)
+
+func Example() {
+	l := buffered.New(0)
+	warn.Warn(l, "Written with warn level")
+	warn.Warnf(l, "%X", "Written with warn level in hex values")
+	// A logger not implementing warn.Logger.
+	warn.Warn(struct{ logr.Logger }{l}, "Written with info level")
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// WARN Written with warn level
+	// WARN 5772697474656E2077697468207761726E206C6576656C20696E206865782076616C756573
+	// INFO Written with info level
+}
@@ -2,77 +2,223 @@ package writer_adapter
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/bketelsen/logr"
 )
 
-// NewBuffered creates a line buffered bufferedWriter which writes lines to the given function.
+// DefaultMaxLineLength is the maximum line length used unless changed using BufferedWriter.SetMaxLineLength.
+const DefaultMaxLineLength = 64 * 1024
+
+// ErrClosed is returned when writing to a buffered writer which was already closed.
+var ErrClosed = errors.New("writer_adapter: write to closed writer")
+
+// NewBuffered creates a line buffered writer which writes lines to the given function. See BufferedWriter.
 func NewBuffered(out func(args ...interface{})) io.WriteCloser {
-	return &bufferedWriter{out, &bytes.Buffer{}}
+	return NewBufferedWriter(out)
 }
 
-// NewBufferedInfoWriter creates a new bufferedWriter which writes lines to the given logger using info level.
+// NewBufferedInfoWriter creates a new line buffered writer which writes lines to the given logger using info level.
 func NewBufferedInfoWriter(l logr.InfoLogger) io.WriteCloser {
 	return NewBuffered(l.Info)
 }
 
-// NewBufferedErrorWriter creates a new bufferedWriter which writes lines to the given logger using error level.
+// NewBufferedErrorWriter creates a new line buffered writer which writes lines to the given logger using error level.
 func NewBufferedErrorWriter(l logr.Logger) io.WriteCloser {
 	return NewBuffered(l.Error)
 }
 
-type bufferedWriter struct {
-	out func(args ...interface{})
-	buf *bytes.Buffer
+// NewBufferedWriter works the same way as NewBuffered, but returns the BufferedWriter itself, allowing to change its
+// settings.
+func NewBufferedWriter(out func(args ...interface{})) *BufferedWriter {
+	return NewBufferedSink(func(line string) error {
+		out(line)
+		return nil
+	})
 }
 
-func (w bufferedWriter) Write(p []byte) (n int, err error) {
-	if n, err = w.buf.Write(p); err != nil {
-		return
+// NewBufferedSink creates a BufferedWriter which writes lines to the given sink. Errors returned by the sink are
+// returned by Write or, if the line was flushed due to the flush timeout, by the next call to Write or Close.
+func NewBufferedSink(sink func(line string) error) *BufferedWriter {
+	return &BufferedWriter{sink: sink, max: DefaultMaxLineLength}
+}
+
+// BufferedWriter is the line buffered writer created by NewBuffered.
+//
+// Complete lines are passed on without their terminating newline. A partial line is kept in the buffer until the rest
+// of the line is written, the flush timeout set using SetFlushTimeout expires or the writer is closed. Lines exceeding
+// the maximum line length are split, or truncated if a marker was set using SetTruncateMarker. The writer is safe to be
+// used by multiple goroutines.
+type BufferedWriter struct {
+	mu      sync.Mutex
+	sink    func(line string) error
+	buf     []byte
+	max     int
+	marker  string
+	timeout time.Duration
+	timer   *time.Timer
+	// gen is incremented each time the flush timer is reset or stopped. A timer callback started for a previous
+	// generation was already queued when the timer changed and must not flush.
+	gen uint64
+	// discard is set while skipping the rest of a truncated line.
+	discard bool
+	// err is the error returned by the sink when flushing due to the flush timeout.
+	err    error
+	closed bool
+}
+
+// Write implements io.Writer.Write by buffering p and passing each complete line to the sink.
+func (w *BufferedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if err := w.err; err != nil {
+		w.err = nil
+		return 0, err
 	}
 
-	err = w.writeLines()
+	n := 0
+	for n < len(p) {
+		chunk := p[n:]
+		i := bytes.IndexByte(chunk, '\n')
+		if i >= 0 {
+			chunk = chunk[:i]
+		}
+		if err := w.append(chunk); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		if i < 0 {
+			break
+		}
+		n++
+		if err := w.endLine(); err != nil {
+			return n, err
+		}
+	}
+	w.resetTimer()
 
-	return
+	return n, nil
 }
 
-func (w bufferedWriter) Close() error {
-	if line := w.buf.String(); len(line) > 0 {
-		w.out(line)
+// Close implements io.Closer.Close by passing the partial line kept in the buffer to the sink. It returns the error of
+// the sink, if any. Calling Close more than once has no effect and returns nil.
+func (w *BufferedWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
 	}
-	w.buf.Reset()
-	return nil
-}
 
-func (w bufferedWriter) writeLines() error {
-	for {
-		line, err := w.buf.ReadString('\n')
-		if len(line) > 0 {
-			if err := w.writeLine(line); err != nil {
-				return err
-			}
+	err := w.err
+	w.err = nil
+	if len(w.buf) > 0 {
+		if ferr := w.flush(""); err == nil {
+			err = ferr
 		}
+	}
 
-		if err == io.EOF {
-			break
+	return err
+}
+
+// SetMaxLineLength sets the maximum length of a line in bytes. Longer lines are split into several lines, unless a
+// marker was set using SetTruncateMarker. A value of zero or less disables the limit.
+func (w *BufferedWriter) SetMaxLineLength(n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.max = n
+}
+
+// SetTruncateMarker makes the writer truncate lines longer than the maximum line length instead of splitting them.
+// The marker is appended to the truncated line and the rest of the line is discarded. An empty marker switches back to
+// splitting.
+func (w *BufferedWriter) SetTruncateMarker(marker string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.marker = marker
+}
+
+// SetFlushTimeout sets the time after which a partial line is passed to the sink if no further data was written. The
+// rest of the line is then treated as a new line. A value of zero or less disables the timeout, which is the default.
+func (w *BufferedWriter) SetFlushTimeout(d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timeout = d
+	w.resetTimer()
+}
+
+// append adds b, which must not contain a newline, to the current line while enforcing the maximum line length.
+func (w *BufferedWriter) append(b []byte) error {
+	if w.discard {
+		return nil
+	}
+	for w.max > 0 && len(w.buf)+len(b) > w.max {
+		n := w.max - len(w.buf)
+		if n < 0 {
+			n = 0
+		}
+		w.buf = append(w.buf, b[:n]...)
+		b = b[n:]
+		if len(w.marker) > 0 {
+			w.discard = true
+			return w.flush(w.marker)
 		}
-		if err != nil {
+		if err := w.flush(""); err != nil {
 			return err
 		}
 	}
+	w.buf = append(w.buf, b...)
 
 	return nil
 }
 
-func (w bufferedWriter) writeLine(line string) error {
-	if line[len(line)-1] == '\n' {
-		w.out(line[:len(line)-1])
-	} else {
-		if _, err := w.buf.WriteString(line); err != nil {
-			return err
-		}
+// endLine passes the current line to the sink, unless it was already passed on due to being truncated.
+func (w *BufferedWriter) endLine() error {
+	if w.discard {
+		w.discard = false
+		return nil
 	}
 
-	return nil
+	return w.flush("")
+}
+
+func (w *BufferedWriter) flush(suffix string) error {
+	line := string(w.buf) + suffix
+	w.buf = w.buf[:0]
+
+	return w.sink(line)
+}
+
+// resetTimer (re)starts the flush timer if there is a partial line in the buffer and stops it otherwise.
+func (w *BufferedWriter) resetTimer() {
+	w.gen++
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	if w.timeout <= 0 || len(w.buf) == 0 {
+		return
+	}
+	gen := w.gen
+	w.timer = time.AfterFunc(w.timeout, func() { w.flushIdle(gen) })
+}
+
+// flushIdle passes the partial line to the sink, unless the timer was reset or stopped since it was started for gen.
+func (w *BufferedWriter) flushIdle(gen uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed || gen != w.gen || len(w.buf) == 0 {
+		return
+	}
+	if err := w.flush(""); err != nil && w.err == nil {
+		w.err = err
+	}
 }
@@ -0,0 +1,75 @@
+package writer_adapter
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/bketelsen/logr"
+)
+
+// Cmd is an exec.Cmd whose output is written to a logger. See AttachCmd.
+type Cmd struct {
+	*exec.Cmd
+	logger   logr.Logger
+	once     sync.Once
+	prefixed logr.Logger
+	stdout   *BufferedWriter
+	stderr   *BufferedWriter
+}
+
+// AttachCmd sets Stdout and Stderr of the not yet started cmd to line buffered writers passing each line to the logger.
+// Lines written to stdout are written using l.V(v).Info and lines written to stderr using l.Error. The logger is
+// prefixed with the base name of the command and its PID, e.g. "ls[4242]". How the prefix is separated from the line is
+// up to the logger, see for example std.Logger.SetPrefixTerminator.
+//
+// Use Run or Wait of the returned Cmd, instead of the ones of cmd, to have partial lines written once the command
+// exited.
+func AttachCmd(cmd *exec.Cmd, l logr.Logger, v int) *Cmd {
+	c := &Cmd{Cmd: cmd, logger: l}
+	c.stdout = NewBufferedWriter(func(args ...interface{}) {
+		c.prefixedLogger().V(v).Info(args...)
+	})
+	c.stderr = NewBufferedWriter(func(args ...interface{}) {
+		c.prefixedLogger().Error(args...)
+	})
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+
+	return c
+}
+
+// Run starts the command and waits for it to complete. See Wait.
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		c.flush()
+		return err
+	}
+
+	return c.Wait()
+}
+
+// Wait waits for the command to exit and for its output to be copied, as exec.Cmd.Wait does. Partial lines not
+// terminated by a newline are written afterwards.
+func (c *Cmd) Wait() error {
+	err := c.Cmd.Wait()
+	c.flush()
+
+	return err
+}
+
+func (c *Cmd) flush() {
+	c.stdout.Close()
+	c.stderr.Close()
+}
+
+// prefixedLogger returns the logger prefixed with name and PID of the command. As output is only written after the
+// command was started, the PID is always known by then.
+func (c *Cmd) prefixedLogger() logr.Logger {
+	c.once.Do(func() {
+		c.prefixed = c.logger.NewWithPrefix(fmt.Sprintf("%s[%d]", filepath.Base(c.Path), c.Process.Pid))
+	})
+
+	return c.prefixed
+}
@@ -0,0 +1,192 @@
+package writer_adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/warn"
+)
+
+const (
+	// LevelError is the level returned by a Detector for lines to be written using error level.
+	LevelError = -1
+	// LevelWarn is the level returned by a Detector for lines to be written using warn.Warn.
+	LevelWarn = -2
+)
+
+// DefaultLevels maps the level names commonly used by logging libraries to the levels returned by a Detector.
+var DefaultLevels = map[string]int{
+	"panic":    LevelError,
+	"fatal":    LevelError,
+	"critical": LevelError,
+	"crit":     LevelError,
+	"error":    LevelError,
+	"err":      LevelError,
+	"warning":  LevelWarn,
+	"warn":     LevelWarn,
+	"notice":   0,
+	"info":     0,
+	"debug":    1,
+	"trace":    2,
+}
+
+// Detector detects the level of a line. If a level is detected, it returns the level and the line with the token
+// defining the level removed. Names of levels are looked up case insensitive.
+type Detector func(line string) (level int, msg string, ok bool)
+
+// DefaultDetectors returns the detectors used by NewLevelWriter if none are passed. Those are, in that order, a
+// JSONDetector and a LogfmtDetector both using the key "level" and a KeywordDetector, all using DefaultLevels.
+func DefaultDetectors() []Detector {
+	return []Detector{
+		JSONDetector("level", DefaultLevels),
+		LogfmtDetector("level", DefaultLevels),
+		KeywordDetector(DefaultLevels),
+	}
+}
+
+// NewLevelWriter creates a writer which directly writes each line to the logger using the level detected by the first
+// detector matching the line. Lines with level LevelError are written using Error, lines with level LevelWarn using
+// warn.Warn and all other lines using V(level). Lines without a detected level are written using Info. If no detectors
+// are passed, DefaultDetectors are used.
+//
+// The writer splits lines the same way the writer returned by New does.
+func NewLevelWriter(l logr.Logger, detectors ...Detector) *Writer {
+	return NewWriter(detect(l, detectors))
+}
+
+// KeywordDetector detects the level by the first word of the line, like "[warn]", "Debug:" or "ERROR". To not mistake
+// prose like "Notice that the cache is cold" for a level, the word must either be enclosed in brackets, be followed by
+// a colon or be written in upper case. The word, including the brackets or colon and the whitespace following it, is
+// removed from the message. If nothing else is left, the line is kept as message.
+func KeywordDetector(levels map[string]int) Detector {
+	return func(line string) (int, string, bool) {
+		rest := strings.TrimLeft(line, " \t")
+		bracket := strings.HasPrefix(rest, "[")
+		if bracket {
+			rest = rest[1:]
+		}
+		word := rest
+		if i := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsLetter(r) }); i >= 0 {
+			word, rest = rest[:i], rest[i:]
+		} else {
+			rest = ""
+		}
+		level, ok := levels[strings.ToLower(word)]
+		if !ok {
+			return 0, line, false
+		}
+
+		switch {
+		case bracket && strings.HasPrefix(rest, "]"), !bracket && strings.HasPrefix(rest, ":"):
+			rest = rest[1:]
+		case !bracket && word == strings.ToUpper(word) && (rest == "" || rest[0] == ' ' || rest[0] == '\t'):
+		default:
+			return 0, line, false
+		}
+		if msg := strings.TrimLeft(rest, " \t"); msg != "" {
+			return level, msg, true
+		}
+
+		return level, line, true
+	}
+}
+
+// LogfmtDetector detects the level by a logfmt pair with the given key, like level=debug or level="info". The pair is
+// removed from the message. Quoted values are skipped, so a pair within a value like msg="a level=error" is ignored.
+func LogfmtDetector(key string, levels map[string]int) Detector {
+	return func(line string) (int, string, bool) {
+		for start := 0; start < len(line); {
+			if line[start] == ' ' {
+				start++
+				continue
+			}
+			end := logfmtPairEnd(line, start)
+			if value, ok := strings.CutPrefix(line[start:end], key+"="); ok {
+				if unquoted, err := strconv.Unquote(value); err == nil {
+					value = unquoted
+				}
+				if level, ok := levels[strings.ToLower(value)]; ok {
+					return level, strings.TrimSpace(line[:start] + strings.TrimPrefix(line[end:], " ")), true
+				}
+			}
+			start = end
+		}
+
+		return 0, line, false
+	}
+}
+
+// logfmtPairEnd returns the index of the first space following start, which is not part of a quoted value.
+func logfmtPairEnd(line string, start int) int {
+	quoted := false
+	for i := start; i < len(line); i++ {
+		switch {
+		case quoted && line[i] == '\\':
+			i++
+		case line[i] == '"':
+			quoted = !quoted
+		case !quoted && line[i] == ' ':
+			return i
+		}
+	}
+
+	return len(line)
+}
+
+// JSONDetector detects the level of lines being a JSON object by the string value of the given key. The key is
+// removed from the object. As the object is encoded again, the members of the message are sorted by their key.
+func JSONDetector(key string, levels map[string]int) Detector {
+	return func(line string) (int, string, bool) {
+		if !strings.HasPrefix(strings.TrimSpace(line), "{") {
+			return 0, line, false
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+			return 0, line, false
+		}
+		var value string
+		if err := json.Unmarshal(obj[key], &value); err != nil {
+			return 0, line, false
+		}
+		level, ok := levels[strings.ToLower(value)]
+		if !ok {
+			return 0, line, false
+		}
+		delete(obj, key)
+		msg, err := json.Marshal(obj)
+		if err != nil {
+			return 0, line, false
+		}
+
+		return level, string(msg), true
+	}
+}
+
+// detect returns a logger function writing each line using the level detected by the first matching detector.
+func detect(l logr.Logger, detectors []Detector) func(args ...interface{}) {
+	if len(detectors) == 0 {
+		detectors = DefaultDetectors()
+	}
+
+	return func(args ...interface{}) {
+		line := fmt.Sprint(args...)
+		for _, d := range detectors {
+			if level, msg, ok := d(line); ok {
+				switch level {
+				case LevelError:
+					l.Error(msg)
+				case LevelWarn:
+					warn.Warn(l, msg)
+				default:
+					l.V(level).Info(msg)
+				}
+				return
+			}
+		}
+		l.Info(line)
+	}
+}
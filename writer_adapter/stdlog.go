@@ -0,0 +1,41 @@
+package writer_adapter
+
+import (
+	"log"
+	"sync"
+
+	"github.com/bketelsen/logr"
+)
+
+// RedirectStdLog redirects the output of the standard library log package to the given logger using info level.
+//
+// Each line written by the standard library logger is passed to the logger individually. The flags and the prefix of
+// the standard library logger are cleared, so the timestamp and prefix are not part of the message. The returned
+// function restores the previous output, flags and prefix. It is safe to be called multiple times.
+func RedirectStdLog(l logr.Logger) (undo func()) {
+	return redirectStdLog(l.Info)
+}
+
+// RedirectStdLogWithLevels works the same way as RedirectStdLog, but writes each line using the level detected by the
+// DefaultDetectors. See NewLevelWriter.
+func RedirectStdLogWithLevels(l logr.Logger) (undo func()) {
+	return redirectStdLog(detect(l, nil))
+}
+
+func redirectStdLog(out func(args ...interface{})) func() {
+	w := NewBuffered(out)
+	prevOut, prevFlags, prevPrefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(w)
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			log.SetOutput(prevOut)
+			log.SetFlags(prevFlags)
+			log.SetPrefix(prevPrefix)
+			w.Close()
+		})
+	}
+}
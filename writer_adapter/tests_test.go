@@ -1,10 +1,32 @@
 package writer_adapter_test
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	stdlog "log"
 	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
 
+	"github.com/bketelsen/logr"
 	"github.com/corvus-ch/logr/buffered"
+	"github.com/corvus-ch/logr/std"
+	"github.com/corvus-ch/logr/verbosity"
 	"github.com/corvus-ch/logr/writer_adapter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The constructors keep returning interfaces, so they remain assignable to variables of those function types.
+var (
+	_ func(func(args ...interface{})) io.Writer = writer_adapter.New
+	_ func(logr.InfoLogger) io.Writer           = writer_adapter.NewInfoWriter
+	_ func(logr.Logger) io.Writer               = writer_adapter.NewErrorWriter
 )
 
 func Example() {
@@ -23,6 +45,25 @@ func Example() {
 	// ERROR and I am an error too.
 }
 
+func Example_lines() {
+	l := buffered.New(0)
+	w := writer_adapter.NewWriter(l.Info)
+	w.Write([]byte("Each line\r\nis written\n\nas separate entry\n"))
+	w.Write([]byte("Partial lines"))
+	w.Write([]byte("are written immediately\n"))
+	w.SetLinePolicy(0)
+	w.Write([]byte("Empty lines are kept\n\n"))
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO Each line
+	// INFO is written
+	// INFO as separate entry
+	// INFO Partial lines
+	// INFO are written immediately
+	// INFO Empty lines are kept
+	// INFO
+}
+
 func Example_buffered() {
 	l := buffered.New(0)
 	w1 := writer_adapter.NewBufferedInfoWriter(l)
@@ -45,3 +86,170 @@ func Example_buffered() {
 	// INFO and I am need to wait until the close
 	// ERROR and I am an error which needs to wait too.
 }
+
+func Example_redirectStdLog() {
+	l := buffered.New(0)
+	undo := writer_adapter.RedirectStdLogWithLevels(l.NewWithPrefix("stdlib: "))
+	stdlog.Print("Written by a library using the standard library logger")
+	stdlog.Printf("[ERROR] %s", "Lines starting with an error keyword are written with error level")
+	stdlog.Print("warn: Warnings are written with warn level")
+	undo()
+	l.Buf().WriteTo(os.Stdout)
+	fmt.Println(stdlog.Writer() == os.Stderr, stdlog.Flags() == stdlog.LstdFlags)
+	// Output:
+	// INFO stdlib: Written by a library using the standard library logger
+	// ERROR stdlib: Lines starting with an error keyword are written with error level
+	// WARN stdlib: Warnings are written with warn level
+	// true true
+}
+
+func Example_bufferedLimits() {
+	l := buffered.New(0)
+	w := writer_adapter.NewBufferedWriter(l.Info)
+	w.SetMaxLineLength(16)
+	w.Write([]byte("Long lines are split into several lines\n"))
+	w.SetTruncateMarker("…")
+	w.Write([]byte("Or truncated using a marker\n"))
+	w.Close()
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO Long lines are s
+	// INFO plit into severa
+	// INFO l lines
+	// INFO Or truncated usi…
+}
+
+func TestBufferedFlushTimeout(t *testing.T) {
+	l := buffered.New(0)
+	w := writer_adapter.NewBufferedWriter(l.Info)
+	w.SetFlushTimeout(10 * time.Millisecond)
+	w.Write([]byte("partial"))
+	assert.Eventually(t, func() bool {
+		l.Mutex().Lock()
+		defer l.Mutex().Unlock()
+		return l.Buf().String() == "INFO partial\n"
+	}, time.Second, time.Millisecond)
+	w.Write([]byte(" line\n"))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "INFO partial\nINFO  line\n", l.Buf().String())
+}
+
+func TestBufferedFlushTimeoutReset(t *testing.T) {
+	l := buffered.New(0)
+	w := writer_adapter.NewBufferedWriter(l.Info)
+	w.SetFlushTimeout(50 * time.Millisecond)
+	for _, part := range []string{"a ", "line ", "written ", "in ", "several ", "parts"} {
+		w.Write([]byte(part))
+		time.Sleep(5 * time.Millisecond)
+	}
+	w.Write([]byte("\n"))
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "INFO a line written in several parts\n", l.Buf().String())
+}
+
+func TestBufferedClose(t *testing.T) {
+	errSink := errors.New("sink failed")
+	var lines []string
+	w := writer_adapter.NewBufferedSink(func(line string) error {
+		lines = append(lines, line)
+		return errSink
+	})
+	w.Write([]byte("partial"))
+	assert.Equal(t, errSink, w.Close())
+	assert.NoError(t, w.Close())
+	n, err := w.Write([]byte("too late\n"))
+	assert.Equal(t, 0, n)
+	assert.Equal(t, writer_adapter.ErrClosed, err)
+	assert.Equal(t, []string{"partial"}, lines)
+}
+
+func TestBufferedConcurrent(t *testing.T) {
+	l := buffered.New(0)
+	w := writer_adapter.NewBufferedInfoWriter(l)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				w.Write([]byte("line\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.NoError(t, w.Close())
+	assert.Equal(t, strings.Repeat("INFO line\n", 1000), l.Buf().String())
+}
+
+func TestAttachCmd(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := std.New(1, stdlog.New(buf, "", 0))
+	l.SetPrefixTerminator(": ")
+	l.Verbosity().SetModules(verbosity.Modules{{Pattern: "sh[[]*]", Verbosity: 2}})
+	cmd := writer_adapter.AttachCmd(exec.Command("sh", "-c", "echo out; echo err >&2; printf partial"), l, 2)
+	require.NoError(t, cmd.Run())
+	prefix := fmt.Sprintf("sh[%d]: ", cmd.Process.Pid)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.ElementsMatch(t, []string{
+		prefix + "out",
+		prefix + "err",
+		prefix + "partial",
+	}, lines)
+}
+
+func Example_levels() {
+	l := buffered.New(2)
+	w := writer_adapter.NewLevelWriter(l)
+	fmt.Fprintln(w, "[ERROR] Keywords are detected at the beginning of a line")
+	fmt.Fprintln(w, "time=12:00 level=debug msg=\"So are logfmt pairs\"")
+	fmt.Fprintln(w, `{"level":"trace","msg":"And JSON objects"}`)
+	fmt.Fprintln(w, "Lines without level are written with info level")
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// ERROR Keywords are detected at the beginning of a line
+	// V[1] time=12:00 msg="So are logfmt pairs"
+	// V[2] {"msg":"And JSON objects"}
+	// INFO Lines without level are written with info level
+}
+
+func TestDetectors(t *testing.T) {
+	keyword := writer_adapter.KeywordDetector(writer_adapter.DefaultLevels)
+	logfmt := writer_adapter.LogfmtDetector("level", writer_adapter.DefaultLevels)
+	json := writer_adapter.JSONDetector("level", writer_adapter.DefaultLevels)
+	tests := []struct {
+		name     string
+		detector writer_adapter.Detector
+		line     string
+		level    int
+		msg      string
+		ok       bool
+	}{
+		{"keyword", keyword, "Warn: disk almost full", writer_adapter.LevelWarn, "disk almost full", true},
+		{"keyword brackets", keyword, "[FATAL] out of memory", writer_adapter.LevelError, "out of memory", true},
+		{"keyword not first", keyword, "no error occurred", 0, "no error occurred", false},
+		{"keyword upper case", keyword, "  DEBUG\tcache miss", 1, "cache miss", true},
+		{"keyword lone", keyword, "ERROR", writer_adapter.LevelError, "ERROR", true},
+		{"keyword lone brackets", keyword, "[warn] ", writer_adapter.LevelWarn, "[warn] ", true},
+		{"keyword unclosed bracket", keyword, "[warn disk almost full", 0, "[warn disk almost full", false},
+		{"prose notice", keyword, "Notice that the cache is cold", 0, "Notice that the cache is cold", false},
+		{"prose critical", keyword, "Critical section entered", 0, "Critical section entered", false},
+		{"prose lone", keyword, "Error", 0, "Error", false},
+		{"logfmt quoted", logfmt, `msg=started level="INFO" port=80`, 0, "msg=started port=80", true},
+		{"logfmt other key", logfmt, "loglevel=debug msg=started", 0, "loglevel=debug msg=started", false},
+		{"logfmt quoted pair", logfmt, `msg="a level=error b"`, 0, `msg="a level=error b"`, false},
+		{"logfmt after quoted space", logfmt, `msg="a b" level=warn`, writer_adapter.LevelWarn, `msg="a b"`, true},
+		{"logfmt unknown level", logfmt, "level=verbose msg=started", 0, "level=verbose msg=started", false},
+		{"json", json, `{"level":"ERROR","msg":"failed"}`, writer_adapter.LevelError, `{"msg":"failed"}`, true},
+		{"json without level", json, `{"msg":"failed"}`, 0, `{"msg":"failed"}`, false},
+		{"json invalid", json, `{"level":"error"`, 0, `{"level":"error"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, msg, ok := tt.detector(tt.line)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.msg, msg)
+			assert.Equal(t, tt.level, level)
+		})
+	}
+}
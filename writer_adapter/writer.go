@@ -2,13 +2,29 @@ package writer_adapter
 
 import (
 	"io"
+	"strings"
 
 	"github.com/bketelsen/logr"
 )
 
-// New creates a writer which directly writes to the given logger function.
+// LinePolicy defines how lines are treated before being passed to the logger. Policies can be combined using a bitwise
+// or.
+type LinePolicy int
+
+const (
+	// TrimCR removes a carriage return at the end of a line, so lines terminated by "\r\n" are treated the same way as
+	// lines terminated by "\n".
+	TrimCR LinePolicy = 1 << iota
+	// SkipEmpty drops empty lines instead of passing them to the logger as empty message.
+	SkipEmpty
+
+	// DefaultLinePolicy is the policy used unless changed using Writer.SetLinePolicy.
+	DefaultLinePolicy = TrimCR | SkipEmpty
+)
+
+// New creates a writer which directly writes to the given logger function. See Writer.
 func New(out func(args ...interface{})) io.Writer {
-	return &writer{out}
+	return NewWriter(out)
 }
 
 // NewInfoWriter creates a writer which directly writes to the given logger using info level.
@@ -16,16 +32,54 @@ func NewInfoWriter(l logr.InfoLogger) io.Writer {
 	return New(l.Info)
 }
 
-// NewInfoWriter creates a writer which directly writes to the given logger using error level.
+// NewErrorWriter creates a writer which directly writes to the given logger using error level.
 func NewErrorWriter(l logr.Logger) io.Writer {
 	return New(l.Error)
 }
 
-type writer struct {
-	out func(args ...interface{})
+// NewWriter works the same way as New, but returns the Writer itself, allowing to change its line policy.
+func NewWriter(out func(args ...interface{})) *Writer {
+	return &Writer{out, DefaultLinePolicy}
+}
+
+// Writer is the writer created by New.
+//
+// Data passed to Write is split into lines and each line is passed to the logger individually, without its terminating
+// newline. A partial line at the end of the data is passed on immediately instead of waiting for the rest of the line.
+// Use NewBuffered to have partial lines joined. How carriage returns and empty lines are treated can be changed using
+// SetLinePolicy.
+type Writer struct {
+	out    func(args ...interface{})
+	policy LinePolicy
 }
 
-func (w writer) Write(p []byte) (int, error) {
-	w.out(string(p))
+// Write implements io.Writer.Write by passing each line of p to the logger. It never fails.
+func (w Writer) Write(p []byte) (int, error) {
+	data := string(p)
+	for len(data) > 0 {
+		line := data
+		if i := strings.IndexByte(data, '\n'); i >= 0 {
+			line, data = data[:i], data[i+1:]
+		} else {
+			data = ""
+		}
+		w.writeLine(line)
+	}
+
 	return len(p), nil
 }
+
+// SetLinePolicy sets how carriage returns and empty lines are treated.
+func (w *Writer) SetLinePolicy(policy LinePolicy) {
+	w.policy = policy
+}
+
+func (w Writer) writeLine(line string) {
+	if w.policy&TrimCR != 0 {
+		line = strings.TrimSuffix(line, "\r")
+	}
+	if len(line) == 0 && w.policy&SkipEmpty != 0 {
+		return
+	}
+	w.out(line)
+}
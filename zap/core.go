@@ -0,0 +1,120 @@
+package zap
+
+import (
+	"sort"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/warn"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewCore creates a zapcore.Core writing to the given logr.Logger. Use zap.New(NewCore(l)) to pass the logger to code
+// requiring a *zap.Logger.
+//
+// Entries with zapcore.ErrorLevel or above are written using logr.Logger.Error. Entries with zapcore.WarnLevel are
+// written using warn.Warn and entries with zapcore.InfoLevel using logr.Logger.Info. Lower levels are written with
+// logr.Logger.V: zapcore.DebugLevel becomes V(1), zapcore.DebugLevel-1 becomes V(2) and so forth.
+//
+// The name of the zap.Logger is passed to logr.Logger.NewWithPrefix. Fields are encoded using
+// zapcore.MapObjectEncoder and added using logr.Logger.WithField. Keys of fields within a namespace are qualified with
+// the namespace, separated by a dot. The stack trace of an entry is added as field named "stacktrace".
+//
+// If the logger has a WithCallerSkip method, like the loggers of this project, the frames of zap and the core are
+// skipped, so the caller reported is the one of the zap.Logger method.
+func NewCore(l logr.Logger) zapcore.Core {
+	if cs, ok := l.(interface{ WithCallerSkip(int) logr.Logger }); ok {
+		l = cs.WithCallerSkip(coreCallerSkip)
+	}
+
+	return &core{logger: l}
+}
+
+// coreCallerSkip is the number of frames between the caller of a zap.Logger method and core.Write.
+const coreCallerSkip = 3
+
+type core struct {
+	logger    logr.Logger
+	namespace string
+}
+
+// Enabled implements zapcore.LevelEnabler.Enabled.
+func (c *core) Enabled(level zapcore.Level) bool {
+	if level >= zapcore.ErrorLevel {
+		return true
+	}
+
+	return c.logger.V(vLevel(level)).Enabled()
+}
+
+// With implements zapcore.Core.With.
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	l, namespace := withFields(c.logger, c.namespace, fields)
+	return &core{logger: l, namespace: namespace}
+}
+
+// Check implements zapcore.Core.Check.
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+// Write implements zapcore.Core.Write.
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	l := c.logger
+	if len(ent.LoggerName) > 0 {
+		l = l.NewWithPrefix(ent.LoggerName)
+	}
+	l, _ = withFields(l, c.namespace, fields)
+	if len(ent.Stack) > 0 {
+		l = l.WithField("stacktrace", ent.Stack)
+	}
+
+	switch {
+	case ent.Level >= zapcore.ErrorLevel:
+		l.Error(ent.Message)
+	case ent.Level == zapcore.WarnLevel:
+		warn.Warn(l, ent.Message)
+	default:
+		l.V(vLevel(ent.Level)).Info(ent.Message)
+	}
+
+	return nil
+}
+
+// Sync implements zapcore.Core.Sync. As logr.Logger has no concept of syncing, it does nothing.
+func (c *core) Sync() error {
+	return nil
+}
+
+// withFields adds the fields to the logger. It returns the logger and the namespace in effect after the fields.
+func withFields(l logr.Logger, namespace string, fields []zapcore.Field) (logr.Logger, string) {
+	for _, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			namespace += f.Key + "."
+			continue
+		}
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		keys := make([]string, 0, len(enc.Fields))
+		for k := range enc.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			l = l.WithField(namespace+k, enc.Fields[k])
+		}
+	}
+
+	return l, namespace
+}
+
+func vLevel(level zapcore.Level) int {
+	if level >= zapcore.InfoLevel {
+		return 0
+	}
+
+	return int(zapcore.InfoLevel - level)
+}
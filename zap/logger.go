@@ -5,83 +5,209 @@ import (
 	"fmt"
 
 	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/internal/errs"
+	"github.com/corvus-ch/logr/internal/kv"
+	"github.com/corvus-ch/logr/verbosity"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // New creates a new instance of logr.Logger.
-func New(verbosity int, l *zap.Logger) *logger {
+//
+// NewWithPrefix is mapped to zap.Logger.Named. Calling NewWithPrefix on a logger which already has a prefix, joins both
+// prefixes using the separator. The separator defaults to "." and can be changed using SetSeparator. A name already set
+// on the zap.Logger passed to New is not part of the prefix. As zap does not expose it, zap joins it with the prefix
+// using "." regardless of the separator. Pass an unnamed zap.Logger to have the separator used throughout.
+//
+// If one of the arguments passed to Error or Errorf is an error, it is added as field using zap.Error. The messages of
+// errors wrapped by it are added as field named "errorCauses". If enabled using SetStackTrace, the stack trace of the
+// call to Error or Errorf is added as field named "stacktrace".
+//
+// Info is written with zapcore.InfoLevel. Sub loggers created using V(n) with n greater than zero write with the level
+// returned by the level mapper, which defaults to DebugLevels and can be changed using SetLevelMapper. Their events
+// carry the field "v" holding n, allowing to filter on the exact verbosity.
+//
+// The zap.Logger is configured using zap.AddCallerSkip, so the caller reported by zap is the caller of the logr.Logger
+// method and not this package. Use WithCallerSkip when wrapping the returned logger.
+func New(v int, l *zap.Logger) *logger {
+	l = l.WithOptions(zap.AddCallerSkip(1))
 	return &logger{
 		level:     0,
-		verbosity: verbosity,
+		verbosity: verbosity.New(v),
 		prefix:    "",
+		separator: ".",
+		mapper:    DebugLevels,
+		base:      l,
 		logger:    l,
 	}
 }
 
+// DebugLevels maps all verbosity levels to zapcore.DebugLevel.
+func DebugLevels(v int) zapcore.Level {
+	return zapcore.DebugLevel
+}
+
+// NegativeLevels maps the verbosity level n to zapcore.Level(-n), the same way github.com/go-logr/zapr does. V(1)
+// becomes zapcore.DebugLevel and any further verbosity level a level below. Note that the zapcore.Core needs to be
+// enabled for those levels, e.g. using zap.NewAtomicLevelAt(-5).
+func NegativeLevels(v int) zapcore.Level {
+	return zapcore.Level(-v)
+}
+
 type logger struct {
 	logr.Logger
 	level     int
-	verbosity int
+	verbosity *verbosity.Level
 	prefix    string
-	logger    *zap.Logger
+	separator string
+	stack     bool
+	mapper    func(v int) zapcore.Level
+	// callerSkip is the number of additional stack frames skipped when capturing the stack trace.
+	callerSkip int
+	// base is the zap.Logger without a name. The name is applied to logger each time it changes.
+	base   *zap.Logger
+	logger *zap.Logger
 }
 
-// Info implements logr.Logger.Info() by writing an event with info level or debug level in case a sub logger was
-// created using V() with level greater than zero.
+// Info implements logr.Logger.Info() by writing an event with info level or the level returned by the level mapper in
+// case a sub logger was created using V() with level greater than zero.
 func (l logger) Info(args ...interface{}) {
 	if l.Enabled() {
-		l.infoDebug(fmt.Sprint(args...))
+		if ce := l.logger.Check(l.zapLevel(), fmt.Sprint(args...)); ce != nil {
+			ce.Write(l.vFields()...)
+		}
 	}
 }
 
-// Infof implements logr.Logger.Infof() by writing an event with info level or debug level in case a sub logger was
-// created using V() with level greater than zero.
+// Infof implements logr.Logger.Infof() by writing an event with info level or the level returned by the level mapper in
+// case a sub logger was created using V() with level greater than zero.
 func (l logger) Infof(format string, args ...interface{}) {
 	if l.Enabled() {
-		l.infoDebug(fmt.Sprintf(format, args...))
+		if ce := l.logger.Check(l.zapLevel(), fmt.Sprintf(format, args...)); ce != nil {
+			ce.Write(l.vFields()...)
+		}
 	}
 }
 
 // Enabled implements logr.Logger.Enabled() by checking if the current verbosity level is less or equal than the loggers
-// maximum verbosity.
+// maximum verbosity for the loggers prefix. The maximum verbosity can be changed at any time using Verbosity, including
+// per prefix overrides.
 func (l logger) Enabled() bool {
-	return l.level <= l.verbosity
+	return l.verbosity.EnabledFor(l.prefix, l.level)
 }
 
 // Error implements logr.Logger.Error() by writing an event with error level.
 func (l logger) Error(args ...interface{}) {
-	l.logger.Error(fmt.Sprint(args...))
+	l.logger.Error(fmt.Sprint(args...), l.errorFields(errs.Find(args))...)
 }
 
 // Errorf implements logr.Logger.Errorf() by writing an event with error level.
 func (l logger) Errorf(format string, args ...interface{}) {
-	l.logger.Error(fmt.Sprintf(format, args...))
+	l.logger.Error(fmt.Sprintf(format, args...), l.errorFields(errs.Find(args))...)
+}
+
+// Warn implements warn.Logger.Warn by writing an event with warn level.
+func (l logger) Warn(args ...interface{}) {
+	l.logger.Warn(fmt.Sprint(args...))
+}
+
+// Warnf implements warn.Logger.Warnf by writing an event with warn level.
+func (l logger) Warnf(format string, args ...interface{}) {
+	l.logger.Warn(fmt.Sprintf(format, args...))
 }
 
 // V implements logr.Logger.V.
 func (l logger) V(level int) logr.InfoLogger {
-	return logger{
-		level:     level,
-		verbosity: l.verbosity,
-		prefix:    l.prefix,
-		logger:    l.logger,
-	}
+	l.level = level
+	return l
 }
 
-// NewWithPrefix implements logr.Logger.NewWithPrefix by adding a field named "prefix".
+// NewWithPrefix implements logr.Logger.NewWithPrefix by joining the prefix with the current one and passing the result
+// to zap.Logger.Named of the zap.Logger passed to New.
 func (l logger) NewWithPrefix(prefix string) logr.Logger {
-	return logger{
-		level:     l.level,
-		verbosity: l.verbosity,
-		prefix:    prefix,
-		logger:    l.logger.With(zap.String("prefix", prefix)),
+	if len(l.prefix) > 0 {
+		prefix = l.prefix + l.separator + prefix
 	}
+	l.prefix = prefix
+	l.logger = l.base.Named(prefix)
+	return l
+}
+
+// WithValues returns a logger which adds the given key/value pairs as fields using zap.Logger.With.
+func (l logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	pairs := kv.Pairs(keysAndValues...)
+	fields := make([]zap.Field, len(pairs))
+	for i, p := range pairs {
+		fields[i] = zap.Any(p.Key, p.Value)
+	}
+	l.base = l.base.With(fields...)
+	l.logger = l.logger.With(fields...)
+	return l
+}
+
+// WithField implements logr.Logger.WithField by calling WithValues with a single key/value pair.
+func (l logger) WithField(name string, value interface{}) logr.Logger {
+	return l.WithValues(name, value)
+}
+
+// WithCallerSkip returns a logger which skips additional stack frames when determining the caller. Wrappers around the
+// logger use it to have the caller of the wrapper reported instead of the wrapper itself. It is mapped to
+// zap.AddCallerSkip.
+func (l logger) WithCallerSkip(skip int) logr.Logger {
+	l.callerSkip += skip
+	l.base = l.base.WithOptions(zap.AddCallerSkip(skip))
+	l.logger = l.logger.WithOptions(zap.AddCallerSkip(skip))
+	return l
+}
+
+// Verbosity returns the verbosity level shared by this logger and all loggers derived from it.
+func (l logger) Verbosity() *verbosity.Level {
+	return l.verbosity
 }
 
-func (l logger) infoDebug(msg string) {
+// SetStackTrace enables or disables capturing the stack trace on calls to Error and Errorf.
+func (l *logger) SetStackTrace(enabled bool) {
+	l.stack = enabled
+}
+
+// SetLevelMapper sets the function mapping the verbosity level of sub loggers created using V(n) with n greater than
+// zero to the zap level. See DebugLevels and NegativeLevels.
+func (l *logger) SetLevelMapper(mapper func(v int) zapcore.Level) {
+	l.mapper = mapper
+}
+
+// SetSeparator sets the separator used to join prefixes of nested calls to NewWithPrefix.
+func (l *logger) SetSeparator(sep string) {
+	l.separator = sep
+}
+
+func (l logger) errorFields(err error) []zap.Field {
+	var fields []zap.Field
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+		if causes := errs.Causes(err); len(causes) > 0 {
+			fields = append(fields, zap.Strings("errorCauses", causes))
+		}
+	}
+	if l.stack {
+		fields = append(fields, zap.String("stacktrace", errs.Stack(2+l.callerSkip)))
+	}
+
+	return fields
+}
+
+func (l logger) zapLevel() zapcore.Level {
+	if l.level > 0 {
+		return l.mapper(l.level)
+	}
+
+	return zapcore.InfoLevel
+}
+
+func (l logger) vFields() []zap.Field {
 	if l.level > 0 {
-		l.logger.Debug(msg)
-	} else {
-		l.logger.Info(msg)
+		return []zap.Field{zap.Int("v", l.level)}
 	}
+
+	return nil
 }
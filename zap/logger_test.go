@@ -1,10 +1,22 @@
 package zap_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/fs"
 	"io/ioutil"
+	stdlog "log"
+	"os"
+	"runtime"
 	"testing"
 
+	"github.com/corvus-ch/logr/buffered"
 	test "github.com/corvus-ch/logr/internal"
+	"github.com/corvus-ch/logr/std"
 	log "github.com/corvus-ch/logr/zap"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -16,7 +28,7 @@ func Example() {
 	l.Infof("%X", "Info level log message printed in hex values")
 	l.Error("Error level log message")
 	l.Errorf("%X", "Error level log message printed in hex values")
-	l.NewWithPrefix("adipiscing").Info("This message has a prefix field")
+	l.NewWithPrefix("adipiscing").Info("This message has a logger name")
 	l.V(1).Info("This message will be printed with debug level")
 	l.V(1).Infof("%X", "This message will be printed with debug level as hex values")
 	l.V(2).Info("This message will not be printed as its verbosity exceeds the maximum")
@@ -25,9 +37,109 @@ func Example() {
 	// {"level":"info","msg":"496E666F206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573"}
 	// {"level":"error","msg":"Error level log message"}
 	// {"level":"error","msg":"4572726F72206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573"}
-	// {"level":"info","msg":"This message has a prefix field","prefix":"adipiscing"}
-	// {"level":"debug","msg":"This message will be printed with debug level"}
-	// {"level":"debug","msg":"54686973206D6573736167652077696C6C206265207072696E7465642077697468206465627567206C6576656C206173206865782076616C756573"}
+	// {"level":"info","logger":"adipiscing","msg":"This message has a logger name"}
+	// {"level":"debug","msg":"This message will be printed with debug level","v":1}
+	// {"level":"debug","msg":"54686973206D6573736167652077696C6C206265207072696E7465642077697468206465627567206C6576656C206173206865782076616C756573","v":1}
+}
+
+func Example_withValues() {
+	l := log.New(0, zap.NewExample())
+	l.WithValues("user", "jane", "attempt", 3).Info("Login failed")
+	l.WithField("path", "/var/lib/dolor sit").Error("Not found")
+	// Output:
+	// {"level":"info","msg":"Login failed","user":"jane","attempt":3}
+	// {"level":"error","msg":"Not found","path":"/var/lib/dolor sit"}
+}
+
+func Example_nestedPrefix() {
+	l := log.New(0, zap.NewExample())
+	l.NewWithPrefix("db").NewWithPrefix("pool").Info("Connection established")
+	l.SetSeparator("/")
+	l.NewWithPrefix("http").WithField("port", 80).NewWithPrefix("server").Info("Listening")
+	// Output:
+	// {"level":"info","logger":"db.pool","msg":"Connection established"}
+	// {"level":"info","logger":"http/server","msg":"Listening","port":80}
+}
+
+func Example_namedBase() {
+	l := log.New(0, zap.NewExample().Named("app"))
+	l.SetSeparator("/")
+	l.NewWithPrefix("http").NewWithPrefix("server").Info("The name of the zap.Logger is always joined using a dot")
+	// Output:
+	// {"level":"info","logger":"app.http/server","msg":"The name of the zap.Logger is always joined using a dot"}
+}
+
+func Example_error() {
+	l := log.New(0, zap.NewExample())
+	err := fmt.Errorf("read config: %w", &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist})
+	l.Error("failed to start: ", err)
+	// Output:
+	// {"level":"error","msg":"failed to start: read config: open /etc/app.conf: file does not exist","error":"read config: open /etc/app.conf: file does not exist","errorCauses":["open /etc/app.conf: file does not exist","file does not exist"]}
+}
+
+func Example_levelMapper() {
+	encoderCfg := zapcore.EncoderConfig{MessageKey: "msg", LevelKey: "level", EncodeLevel: zapcore.LowercaseLevelEncoder}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), zapcore.AddSync(os.Stdout), zap.NewAtomicLevelAt(-3))
+	l := log.New(3, zap.New(core))
+	l.SetLevelMapper(log.NegativeLevels)
+	l.V(1).Info("V(1) is written with debug level")
+	l.V(3).Info("V(3) is written with level -3")
+	// Output:
+	// {"level":"debug","msg":"V(1) is written with debug level","v":1}
+	// {"level":"Level(-3)","msg":"V(3) is written with level -3","v":3}
+}
+
+func TestStackTrace(t *testing.T) {
+	buf := &bytes.Buffer{}
+	encoderCfg := zapcore.EncoderConfig{MessageKey: "msg"}
+	l := log.New(0, zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), zapcore.AddSync(buf), zap.DebugLevel)))
+	l.SetStackTrace(true)
+	l.Error("Error level log message")
+	assert.Regexp(t, `^{"msg":"Error level log message","stacktrace":"github.com/corvus-ch/logr/zap_test.TestStackTrace\\n\\t.*/zap/logger_test.go:\d+\\n`, buf.String())
+}
+
+func TestCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	encoderCfg := zapcore.EncoderConfig{CallerKey: "caller", EncodeCaller: zapcore.FullCallerEncoder}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), zapcore.AddSync(buf), zap.DebugLevel)
+	want := test.Callers(log.New(1, zap.New(core, zap.AddCaller())))
+
+	var got []string
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var entry struct{ Caller string }
+		require.NoError(t, dec.Decode(&entry))
+		got = append(got, entry.Caller)
+	}
+	test.AssertCallers(t, want, got)
+}
+
+func Example_core() {
+	l := buffered.New(1)
+	zl := zap.New(log.NewCore(l))
+	zl.Info("Info level log message", zap.String("user", "jane"), zap.Int("attempt", 3))
+	zl.Named("db").Warn("Warnings are written with warn level")
+	zl.Error("Error level log message", zap.Error(errors.New("connection refused")))
+	zl.Debug("Debug level log message", zap.Namespace("http"), zap.Int("status", 404))
+	if ce := zl.Check(zap.DebugLevel-1, "This message will not be printed as its verbosity exceeds the maximum"); ce != nil {
+		ce.Write()
+	}
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO Info level log message user=jane attempt=3
+	// WARN dbWarnings are written with warn level
+	// ERROR Error level log message error="connection refused"
+	// V[1] Debug level log message http.status=404
+}
+
+func TestCoreCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zl := zap.New(log.NewCore(std.New(0, stdlog.New(buf, "", stdlog.Lshortfile))))
+	_, _, line, _ := runtime.Caller(0)
+	zl.Info("Info level log message")
+	zl.Error("Error level log message")
+	want := fmt.Sprintf("logger_test.go:%d: Info level log message\nlogger_test.go:%d: Error level log message\n", line+1, line+2)
+	assert.Equal(t, want, buf.String())
 }
 
 func Benchmark(b *testing.B) {
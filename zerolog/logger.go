@@ -1,41 +1,85 @@
-// Package std implements logr.Logger by Tim Hockin using Zerolog.
+// Package zerolog implements logr.Logger by Tim Hockin using Zerolog.
 package zerolog
 
 import (
 	"fmt"
 
 	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/internal/errs"
+	"github.com/corvus-ch/logr/internal/kv"
+	"github.com/corvus-ch/logr/verbosity"
 	"github.com/rs/zerolog"
 )
 
 // New creates a new instance of logr.Logger.
-func New(verbosity int, l zerolog.Logger) *logger {
+//
+// NewWithPrefix adds a field named "prefix". Calling NewWithPrefix on a logger which already has a prefix, joins both
+// prefixes using the separator and replaces the field. The separator defaults to "." and can be changed using
+// SetSeparator.
+//
+// If one of the arguments passed to Error or Errorf is an error, it is added as field using zerolog.Event.Err. The
+// messages of errors wrapped by it are added as field named "errorCauses". If enabled using SetStackTrace, the stack
+// trace of the call to Error or Errorf is added as field named zerolog.ErrorStackFieldName.
+//
+// Info is written with zerolog.InfoLevel. Sub loggers created using V(n) with n greater than zero write with the level
+// returned by the level mapper, which defaults to DebugLevels and can be changed using SetLevelMapper. Their events
+// carry the field "v" holding n, allowing to filter on the exact verbosity.
+//
+// A zerolog.Logger configured using zerolog.Context.Caller reports this package as the caller. Use SetReportCaller
+// instead, which adds the field named zerolog.CallerFieldName pointing to the caller of the logr.Logger method.
+func New(v int, l zerolog.Logger) *logger {
 	return &logger{
 		level:     0,
-		verbosity: verbosity,
+		verbosity: verbosity.New(v),
 		prefix:    "",
+		separator: ".",
+		mapper:    DebugLevels,
+		base:      l,
 		logger:    l,
 	}
 }
 
+// DebugLevels maps all verbosity levels to zerolog.DebugLevel.
+func DebugLevels(v int) zerolog.Level {
+	return zerolog.DebugLevel
+}
+
+// TraceLevels maps V(1) to zerolog.DebugLevel and any further verbosity level to zerolog.TraceLevel.
+func TraceLevels(v int) zerolog.Level {
+	if v > 1 {
+		return zerolog.TraceLevel
+	}
+
+	return zerolog.DebugLevel
+}
+
 type logger struct {
 	logr.Logger
 	level     int
-	verbosity int
+	verbosity *verbosity.Level
 	prefix    string
-	logger    zerolog.Logger
+	separator string
+	stack     bool
+	caller    bool
+	mapper    func(v int) zerolog.Level
+	// callerSkip is the number of additional stack frames skipped when determining the caller.
+	callerSkip int
+	// base is the zerolog.Logger without the prefix field. As zerolog does not deduplicate fields, the prefix field is
+	// added to logger each time the prefix changes.
+	base   zerolog.Logger
+	logger zerolog.Logger
 }
 
-// Info implements logr.Logger.Info() by writing an event with info level or debug level in case a sub logger was
-// created using V() with level greater than zero.
+// Info implements logr.Logger.Info() by writing an event with info level or the level returned by the level mapper in
+// case a sub logger was created using V() with level greater than zero.
 func (l logger) Info(args ...interface{}) {
 	if l.Enabled() {
 		l.event().Msg(fmt.Sprint(args...))
 	}
 }
 
-// Infof implements logr.Logger.Infof() by writing an event with info level or debug level in case a sub logger was
-// created using V() with level greater than zero.
+// Infof implements logr.Logger.Infof() by writing an event with info level or the level returned by the level mapper in
+// case a sub logger was created using V() with level greater than zero.
 func (l logger) Infof(format string, args ...interface{}) {
 	if l.Enabled() {
 		l.event().Msgf(format, args...)
@@ -43,45 +87,138 @@ func (l logger) Infof(format string, args ...interface{}) {
 }
 
 // Enabled implements logr.Logger.Enabled() by checking if the current verbosity level is less or equal than the loggers
-// maximum verbosity.
+// maximum verbosity for the loggers prefix. The maximum verbosity can be changed at any time using Verbosity, including
+// per prefix overrides.
 func (l logger) Enabled() bool {
-	return l.level <= l.verbosity
+	return l.verbosity.EnabledFor(l.prefix, l.level)
 }
 
 // Error implements logr.Logger.Error() by writing an event with error level.
 func (l logger) Error(args ...interface{}) {
-	l.logger.Error().Msg(fmt.Sprint(args...))
+	l.errorEvent(errs.Find(args)).Msg(fmt.Sprint(args...))
 }
 
 // Errorf implements logr.Logger.Errorf() by writing an event with error level.
 func (l logger) Errorf(format string, args ...interface{}) {
-	l.logger.Error().Msgf(format, args...)
+	l.errorEvent(errs.Find(args)).Msgf(format, args...)
+}
+
+// Warn implements warn.Logger.Warn by writing an event with warn level.
+func (l logger) Warn(args ...interface{}) {
+	l.warnEvent().Msg(fmt.Sprint(args...))
+}
+
+// Warnf implements warn.Logger.Warnf by writing an event with warn level.
+func (l logger) Warnf(format string, args ...interface{}) {
+	l.warnEvent().Msgf(format, args...)
 }
 
 // V implements logr.Logger.V.
 func (l logger) V(level int) logr.InfoLogger {
-	return logger{
-		level:     level,
-		verbosity: l.verbosity,
-		prefix:    l.prefix,
-		logger:    l.logger,
-	}
+	l.level = level
+	return l
 }
 
-// NewWithPrefix implements logr.Logger.NewWithPrefix by adding a field named "prefix".
+// NewWithPrefix implements logr.Logger.NewWithPrefix by joining the prefix with the current one and setting it as
+// field named "prefix".
 func (l logger) NewWithPrefix(prefix string) logr.Logger {
-	return logger{
-		level:     l.level,
-		verbosity: l.verbosity,
-		prefix:    prefix,
-		logger:    l.logger.With().Str("prefix", prefix).Logger(),
+	if len(l.prefix) > 0 {
+		prefix = l.prefix + l.separator + prefix
+	}
+	l.prefix = prefix
+	l.logger = l.base.With().Str("prefix", prefix).Logger()
+	return l
+}
+
+// WithValues returns a logger which adds the given key/value pairs as fields to the zerolog.Context.
+func (l logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	pairs := kv.Pairs(keysAndValues...)
+	l.base = withValues(l.base, pairs)
+	l.logger = withValues(l.logger, pairs)
+	return l
+}
+
+// WithField implements logr.Logger.WithField by calling WithValues with a single key/value pair.
+func (l logger) WithField(name string, value interface{}) logr.Logger {
+	return l.WithValues(name, value)
+}
+
+// WithCallerSkip returns a logger which skips additional stack frames when determining the caller. Wrappers around the
+// logger use it to have the caller of the wrapper reported instead of the wrapper itself.
+func (l logger) WithCallerSkip(skip int) logr.Logger {
+	l.callerSkip += skip
+	return l
+}
+
+// Verbosity returns the verbosity level shared by this logger and all loggers derived from it.
+func (l logger) Verbosity() *verbosity.Level {
+	return l.verbosity
+}
+
+// SetStackTrace enables or disables capturing the stack trace on calls to Error and Errorf.
+func (l *logger) SetStackTrace(enabled bool) {
+	l.stack = enabled
+}
+
+// SetLevelMapper sets the function mapping the verbosity level of sub loggers created using V(n) with n greater than
+// zero to the zerolog level. See DebugLevels and TraceLevels.
+func (l *logger) SetLevelMapper(mapper func(v int) zerolog.Level) {
+	l.mapper = mapper
+}
+
+// SetReportCaller enables or disables adding the caller of the logr.Logger method as field named
+// zerolog.CallerFieldName.
+func (l *logger) SetReportCaller(enabled bool) {
+	l.caller = enabled
+}
+
+// SetSeparator sets the separator used to join prefixes of nested calls to NewWithPrefix.
+func (l *logger) SetSeparator(sep string) {
+	l.separator = sep
+}
+
+func (l logger) errorEvent(err error) *zerolog.Event {
+	e := l.withCaller(l.logger.Error())
+	if err != nil {
+		e = e.Err(err)
+		if causes := errs.Causes(err); len(causes) > 0 {
+			e = e.Strs("errorCauses", causes)
+		}
 	}
+	if l.stack {
+		e = e.Str(zerolog.ErrorStackFieldName, errs.Stack(2+l.callerSkip))
+	}
+
+	return e
 }
 
 func (l logger) event() *zerolog.Event {
 	if l.level > 0 {
-		return l.logger.Debug()
+		return l.withCaller(l.logger.WithLevel(l.mapper(l.level)).Int("v", l.level))
+	}
+
+	return l.withCaller(l.logger.Info())
+}
+
+func (l logger) warnEvent() *zerolog.Event {
+	return l.withCaller(l.logger.Warn())
+}
+
+// withCaller adds the caller field if enabled. It must only be called from within event, errorEvent and warnEvent, as
+// the skipped frames account for those and the logr.Logger method calling them.
+func (l logger) withCaller(e *zerolog.Event) *zerolog.Event {
+	if !l.caller {
+		return e
+	}
+
+	return e.Caller(3 + l.callerSkip)
+}
+
+func withValues(l zerolog.Logger, pairs []kv.Pair) zerolog.Logger {
+	ctx := l.With()
+	for _, p := range pairs {
+		ctx = ctx.Interface(p.Key, p.Value)
 	}
 
-	return l.logger.Info()
+	return ctx.Logger()
 }
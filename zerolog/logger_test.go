@@ -1,13 +1,20 @@
 package zerolog_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"os"
 	"testing"
 
+	"github.com/corvus-ch/logr/buffered"
 	test "github.com/corvus-ch/logr/internal"
 	log "github.com/corvus-ch/logr/zerolog"
 	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
 )
 
 func Example() {
@@ -26,8 +33,78 @@ func Example() {
 	// {"level":"error","message":"Error level log message"}
 	// {"level":"error","message":"4572726F72206C6576656C206C6F67206D657373616765207072696E74656420696E206865782076616C756573"}
 	// {"level":"info","prefix":"adipiscing","message":"This message has a prefix field"}
-	// {"level":"debug","message":"This message will be printed with debug level"}
-	// {"level":"debug","message":"54686973206D6573736167652077696C6C206265207072696E7465642077697468206465627567206C6576656C206173206865782076616C756573"}
+	// {"level":"debug","v":1,"message":"This message will be printed with debug level"}
+	// {"level":"debug","v":1,"message":"54686973206D6573736167652077696C6C206265207072696E7465642077697468206465627567206C6576656C206173206865782076616C756573"}
+}
+
+func Example_withValues() {
+	l := log.New(0, zerolog.New(os.Stdout))
+	l.WithValues("user", "jane", "attempt", 3).Info("Login failed")
+	l.WithField("path", "/var/lib/dolor sit").Error("Not found")
+	// Output:
+	// {"level":"info","user":"jane","attempt":3,"message":"Login failed"}
+	// {"level":"error","path":"/var/lib/dolor sit","message":"Not found"}
+}
+
+func Example_nestedPrefix() {
+	l := log.New(0, zerolog.New(os.Stdout))
+	l.NewWithPrefix("db").NewWithPrefix("pool").Info("Connection established")
+	l.SetSeparator("/")
+	l.NewWithPrefix("http").WithField("port", 80).NewWithPrefix("server").Info("Listening")
+	// Output:
+	// {"level":"info","prefix":"db.pool","message":"Connection established"}
+	// {"level":"info","port":80,"prefix":"http/server","message":"Listening"}
+}
+
+func Example_error() {
+	l := log.New(0, zerolog.New(os.Stdout))
+	err := fmt.Errorf("read config: %w", &fs.PathError{Op: "open", Path: "/etc/app.conf", Err: fs.ErrNotExist})
+	l.Error("failed to start: ", err)
+	// Output:
+	// {"level":"error","error":"read config: open /etc/app.conf: file does not exist","errorCauses":["open /etc/app.conf: file does not exist","file does not exist"],"message":"failed to start: read config: open /etc/app.conf: file does not exist"}
+}
+
+func Example_levelMapper() {
+	l := log.New(2, zerolog.New(os.Stdout).Level(zerolog.TraceLevel))
+	l.SetLevelMapper(log.TraceLevels)
+	l.V(1).Info("V(1) is written with debug level")
+	l.V(2).Info("V(2) is written with trace level")
+	// Output:
+	// {"level":"debug","v":1,"message":"V(1) is written with debug level"}
+	// {"level":"trace","v":2,"message":"V(2) is written with trace level"}
+}
+
+func Example_writer() {
+	l := buffered.New(1)
+	zl := zerolog.New(log.NewWriter(l)).With().Timestamp().Logger()
+	zl.Info().Str("user", "jane").Int("attempt", 3).Msg("Info level log message")
+	zl.Warn().Msg("Warnings are written with warn level")
+	zl.Error().Err(errors.New("connection refused")).Msg("Error level log message")
+	zl.Debug().Msg("Debug level log message")
+	log.New(0, zl).NewWithPrefix("db: ").Info("The prefix of the logr.Logger is restored")
+	l.Buf().WriteTo(os.Stdout)
+	// Output:
+	// INFO Info level log message user=jane attempt=3
+	// WARN Warnings are written with warn level
+	// ERROR Error level log message error="connection refused"
+	// V[1] Debug level log message
+	// INFO db: The prefix of the logr.Logger is restored
+}
+
+func TestCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := log.New(1, zerolog.New(buf))
+	l.SetReportCaller(true)
+	want := test.Callers(l)
+
+	var got []string
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var event struct{ Caller string }
+		require.NoError(t, dec.Decode(&event))
+		got = append(got, event.Caller)
+	}
+	test.AssertCallers(t, want, got)
 }
 
 func Benchmark(b *testing.B) {
@@ -0,0 +1,128 @@
+package zerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bketelsen/logr"
+	"github.com/corvus-ch/logr/warn"
+	"github.com/rs/zerolog"
+)
+
+// NewWriter creates a zerolog.LevelWriter writing the events of a zerolog.Logger to the given logr.Logger. Use
+// zerolog.New(NewWriter(l)) to pass the logger to code requiring a zerolog.Logger.
+//
+// Events with zerolog.ErrorLevel or above are written using logr.Logger.Error. Events with zerolog.WarnLevel are
+// written using warn.Warn and events with zerolog.InfoLevel or without level using logr.Logger.Info. Lower levels are
+// written with logr.Logger.V: zerolog.DebugLevel becomes V(1) and zerolog.TraceLevel becomes V(2).
+//
+// The JSON of the event is decoded. The field named zerolog.MessageFieldName is used as message and a field named
+// "prefix", as written by the logger returned by New, is passed to logr.Logger.NewWithPrefix. The fields named
+// zerolog.LevelFieldName and zerolog.TimestampFieldName are dropped. All other fields are added in order using
+// logr.Logger.WithField. Data not being a JSON object is written as is using logr.Logger.Info.
+func NewWriter(l logr.Logger) zerolog.LevelWriter {
+	return &writer{logger: l}
+}
+
+var errNotObject = errors.New("not a JSON object")
+
+type writer struct {
+	logger logr.Logger
+}
+
+// Write implements io.Writer.Write by writing the event using the level found in the field named
+// zerolog.LevelFieldName.
+func (w *writer) Write(p []byte) (int, error) {
+	l, msg, name, err := decode(w.logger, p)
+	if err != nil {
+		w.writeRaw(p)
+		return len(p), nil
+	}
+	level, err := zerolog.ParseLevel(name)
+	if err != nil {
+		level = zerolog.NoLevel
+	}
+	write(l, level, msg)
+
+	return len(p), nil
+}
+
+// WriteLevel implements zerolog.LevelWriter.WriteLevel.
+func (w *writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < zerolog.ErrorLevel && !w.logger.V(vLevel(level)).Enabled() {
+		return len(p), nil
+	}
+	l, msg, _, err := decode(w.logger, p)
+	if err != nil {
+		w.writeRaw(p)
+		return len(p), nil
+	}
+	write(l, level, msg)
+
+	return len(p), nil
+}
+
+func (w *writer) writeRaw(p []byte) {
+	w.logger.Info(string(bytes.TrimSuffix(p, []byte("\n"))))
+}
+
+func write(l logr.Logger, level zerolog.Level, msg string) {
+	switch {
+	case level >= zerolog.ErrorLevel && level < zerolog.NoLevel:
+		l.Error(msg)
+	case level == zerolog.WarnLevel:
+		warn.Warn(l, msg)
+	default:
+		l.V(vLevel(level)).Info(msg)
+	}
+}
+
+// decode reads the JSON object of an event, applying its fields to the logger in order. It returns the logger, the
+// message and the name of the level.
+func decode(l logr.Logger, p []byte) (logr.Logger, string, string, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, "", "", errNotObject
+	}
+
+	var msg, level string
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, "", "", err
+		}
+		key, _ := t.(string)
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return nil, "", "", err
+		}
+		switch key {
+		case zerolog.LevelFieldName:
+			level = fmt.Sprint(value)
+		case zerolog.TimestampFieldName:
+		case zerolog.MessageFieldName:
+			msg = fmt.Sprint(value)
+		case "prefix":
+			l = l.NewWithPrefix(fmt.Sprint(value))
+		default:
+			l = l.WithField(key, value)
+		}
+	}
+	if _, err := dec.Token(); err != nil && err != io.EOF {
+		return nil, "", "", err
+	}
+
+	return l, msg, level, nil
+}
+
+func vLevel(level zerolog.Level) int {
+	if level >= zerolog.InfoLevel {
+		return 0
+	}
+
+	return int(zerolog.InfoLevel - level)
+}